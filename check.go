@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/civo/node-agent/pkg/watcher"
+)
+
+// Exit codes of the check subcommand.
+const (
+	checkExitHealthy   = 0
+	checkExitUnhealthy = 1
+	checkExitError     = 2
+)
+
+// runCheck evaluates the node pool once, prints the report and returns the exit code.
+func runCheck(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	output := fs.String("output", "table", "Output format, either table or json")
+	kubeconfigPath := fs.String("kubeconfig", defaultKubeconfigPath(), "Path to the kubeconfig, the in-cluster config is used when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: node-agent check [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Evaluates every node in the pool once and prints the action node-agent would take.\n")
+		fmt.Fprintf(fs.Output(), "Exits with %d when all nodes are healthy, %d when a node is unhealthy and %d on error.\n\n",
+			checkExitHealthy, checkExitUnhealthy, checkExitError)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return checkExitError
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "invalid output %q, must be table or json\n", *output)
+		return checkExitError
+	}
+
+	w, err := newCLIWatcher(ctx, *kubeconfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create watcher: %v\n", err)
+		return checkExitError
+	}
+	report, err := w.Check(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to check nodes: %v\n", err)
+		return checkExitError
+	}
+
+	if *output == "json" {
+		err = printReportJSON(os.Stdout, report)
+	} else {
+		err = printReportTable(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to print report: %v\n", err)
+		return checkExitError
+	}
+	if !report.Healthy() {
+		return checkExitUnhealthy
+	}
+	return checkExitHealthy
+}
+
+func printReportJSON(out io.Writer, report *watcher.Report) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func printReportTable(out io.Writer, report *watcher.Report) error {
+	if report.PauseReason != "" {
+		fmt.Fprintf(out, "Remediation is paused: %s\n\n", report.PauseReason)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tINSTANCE\tHEALTHY\tACTION\tRULE\tREASON")
+	for _, n := range report.Nodes {
+		reason := n.Reason
+		if reason == "" {
+			reason = failedChecks(n)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%s\t%s\t%s\n",
+			n.Name, orDash(n.InstanceID), n.Healthy, n.Action, n.Rule, orDash(reason))
+	}
+	return tw.Flush()
+}
+
+// failedChecks joins the reasons of the checks that did not pass.
+func failedChecks(n watcher.NodeReport) string {
+	var reasons []string
+	for _, c := range n.Checks {
+		if !c.Healthy {
+			reasons = append(reasons, c.Name+": "+c.Reason)
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/civo/node-agent/pkg/watcher"
+)
+
+// newCLIWatcher creates the watcher for the subcommands, which run outside of the cluster with the given kubeconfig.
+// Logs go to stderr so that they do not mix with the output of the subcommand.
+func newCLIWatcher(ctx context.Context, kubeconfigPath string) (watcher.Watcher, error) {
+	slog.SetDefault(slog.New(newLogHandler(os.Stderr, slog.LevelWarn, "text")))
+	return newWatcher(ctx, watcher.WithKubernetesClientConfigPath(kubeconfigPath))
+}
+
+// kubeconfigPath returns the first path of the kubeconfig setting, which can be a list like $KUBECONFIG.
+func kubeconfigPath() string {
+	if kubeconfig.value == "" {
+		return ""
+	}
+	return filepath.SplitList(kubeconfig.value)[0]
+}
+
+// defaultKubeconfigPath returns the path of the kubeconfig setting, or ~/.kube/config when it exists.
+func defaultKubeconfigPath() string {
+	if path := kubeconfigPath(); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(home, ".kube", "config")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// parseNodeArgs parses the flags of a subcommand that takes a node name, and returns the node name.
+// Flags are accepted before and after the node name.
+func parseNodeArgs(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	nodeName := fs.Arg(0)
+	if fs.NArg() > 0 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return "", err
+		}
+	}
+	if nodeName == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return "", errors.New("exactly one node name is required")
+	}
+	return nodeName, nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/civo/node-agent/pkg/watcher"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// setting is a configuration value that can be set with a flag or an environment variable.
+// The flag takes precedence over the environment variable, which takes precedence over the default of the watcher.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	// validate returns an error when the value is invalid. It is not called for empty values.
+	validate func(string) error
+
+	flagValue string
+	value     string
+}
+
+// settings are all the settings, in the order they are documented in.
+var settings []*setting
+
+func newSetting(flag, env, usage string, validate func(string) error) *setting {
+	s := &setting{flag: flag, env: env, usage: usage, validate: validate}
+	settings = append(settings, s)
+	return s
+}
+
+var (
+	kubeconfig              = newSetting("kubeconfig", "KUBECONFIG", "Path to the kubeconfig, the in-cluster config is used when empty", nil)
+	apiURL                  = newSetting("api-url", "CIVO_API_URL", "URL of the Civo API", isURL)
+	apiKey                  = newSetting("api-key", "CIVO_API_KEY", "Civo API key, prefer the environment variable to keep it out of the process list", nil)
+	region                  = newSetting("region", "CIVO_REGION", "Civo region of the cluster", nil)
+	clusterID               = newSetting("cluster-id", "CIVO_CLUSTER_ID", "ID of the Kubernetes cluster", nil)
+	nodePoolID              = newSetting("node-pool-id", "CIVO_NODE_POOL_ID", "ID of the node pool to watch", nil)
+	nodeDesiredGPUCount     = newSetting("desired-gpu-count", "CIVO_NODE_DESIRED_GPU_COUNT", "`Number` of GPUs every node must have, 0 disables the check", atLeast(0))
+	rebootTimeWindowMinutes = newSetting("reboot-window", "CIVO_NODE_REBOOT_TIME_WINDOW_MINUTES", "`Minutes` a node must be unhealthy, and must not have been remediated, before it is remediated", atLeast(1))
+	reconcileInterval       = newSetting("interval", "CIVO_NODE_AGENT_RECONCILE_INTERVAL_SECONDS", "`Seconds` between reconciles", atLeast(1))
+	reconcileJitter         = newSetting("interval-jitter", "CIVO_NODE_AGENT_RECONCILE_JITTER_SECONDS", "Maximum `seconds` randomly added to the interval", atLeast(0))
+	reconcileTimeout        = newSetting("reconcile-timeout", "CIVO_NODE_AGENT_RECONCILE_TIMEOUT_SECONDS", "`Seconds` a reconcile may take before it is cancelled, 0 disables it", atLeast(0))
+	nodeStartupGracePeriod  = newSetting("startup-grace-period", "CIVO_NODE_STARTUP_GRACE_PERIOD_MINUTES", "`Minutes` after their creation nodes are not remediated", atLeast(0))
+	optIn                   = newSetting("opt-in", "CIVO_NODE_AGENT_OPT_IN", "Only remediate nodes labelled or annotated with node-agent.civo.com/enabled=true, `true` or false", isBool)
+	skipCordonedNodes       = newSetting("skip-cordoned", "CIVO_NODE_SKIP_CORDONED", "Do not remediate cordoned nodes, `true` or false", isBool)
+	maintenanceTaintKeys    = newSetting("maintenance-taint-keys", "CIVO_NODE_MAINTENANCE_TAINT_KEYS", "Comma-separated taint keys that mark a node as under maintenance", listOf(isQualifiedName))
+	rebootWindows           = newSetting("reboot-windows", "CIVO_NODE_REBOOT_WINDOWS", "Semicolon-separated maintenance windows remediations are limited to", nil)
+	rebootBlackouts         = newSetting("reboot-blackouts", "CIVO_NODE_REBOOT_BLACKOUTS", "Comma-separated periods no remediation is taken in", nil)
+	rebootWindowTimezone    = newSetting("reboot-window-timezone", "CIVO_NODE_REBOOT_WINDOW_TIMEZONE", "Timezone of the maintenance windows", nil)
+	rebootWindowOverride    = newSetting("reboot-window-override", "CIVO_NODE_REBOOT_WINDOW_OVERRIDE_MINUTES", "`Minutes` after which unhealthy nodes are remediated outside of maintenance windows, 0 disables it", atLeast(0))
+	maxRebootsPerNode       = newSetting("max-reboots", "CIVO_NODE_MAX_REBOOTS", "`Number` of reboots within the max reboots period after which a node is quarantined, 0 disables it", atLeast(0))
+	maxRebootsPeriod        = newSetting("max-reboots-period", "CIVO_NODE_MAX_REBOOTS_PERIOD_MINUTES", "`Minutes` the max reboots are counted over", atLeast(1))
+	verificationTimeout     = newSetting("verification-timeout", "CIVO_NODE_VERIFICATION_TIMEOUT_MINUTES", "`Minutes` a remediated node has to recover, 0 uses the reboot window", atLeast(0))
+	remediation             = newSetting("remediation", "CIVO_NODE_REMEDIATION", "Remediation of unhealthy nodes", oneOf(watcher.RemediationHardReboot, watcher.RemediationSoftReboot, watcher.RemediationRecycle, watcher.RemediationDeleteNode, watcher.RemediationTaint))
+	capacityCheckResources  = newSetting("capacity-check-resources", "CIVO_NODE_CAPACITY_CHECK_RESOURCES", "Comma-separated resources whose allocatable must match their capacity", listOf(isQualifiedName))
+	devicePluginNamespace   = newSetting("device-plugin-namespace", "CIVO_NODE_DEVICE_PLUGIN_NAMESPACE", "Namespace of the device plugin pods, enables the device plugin check when set", nil)
+	devicePluginSelector    = newSetting("device-plugin-selector", "CIVO_NODE_DEVICE_PLUGIN_SELECTOR", "Label selector of the device plugin pods", nil)
+	devicePluginRestart     = newSetting("device-plugin-restart-period", "CIVO_NODE_DEVICE_PLUGIN_RESTART_PERIOD_MINUTES", "`Minutes` the device plugin of a node is restarted at most once in", atLeast(1))
+	stuckPodCleanup         = newSetting("stuck-pod-cleanup", "CIVO_NODE_STUCK_POD_CLEANUP", "Force delete stuck pods from remediated nodes, `true` or false", isBool)
+	stuckPodNamespaces      = newSetting("stuck-pod-namespaces", "CIVO_NODE_STUCK_POD_NAMESPACES", "Comma-separated namespaces the stuck pod cleanup is limited to", nil)
+	stuckPodSelector        = newSetting("stuck-pod-selector", "CIVO_NODE_STUCK_POD_SELECTOR", "Label selector the stuck pod cleanup is limited to", nil)
+	webhookURL              = newSetting("webhook-url", "CIVO_NODE_WEBHOOK_URL", "URL notifications are sent to", isURL)
+	webhookTemplate         = newSetting("webhook-template", "CIVO_NODE_WEBHOOK_TEMPLATE", "Go template of the notification payload", nil)
+	webhookEvents           = newSetting("webhook-events", "CIVO_NODE_WEBHOOK_EVENTS", "Comma-separated events notifications are sent for", listOf(oneOf(string(watcher.NotificationUnhealthy), string(watcher.NotificationRemediated), string(watcher.NotificationSkipped), string(watcher.NotificationFailed))))
+	webhookRetries          = newSetting("webhook-retries", "CIVO_NODE_WEBHOOK_RETRIES", "`Number` of retries of failed notifications", atLeast(0))
+	approvalURL             = newSetting("approval-url", "CIVO_NODE_APPROVAL_URL", "URL every remediation is approved by", isURL)
+	approvalTimeout         = newSetting("approval-timeout", "CIVO_NODE_APPROVAL_TIMEOUT_SECONDS", "`Seconds` the approver has to answer", atLeast(1))
+	approvalDefault         = newSetting("approval-default", "CIVO_NODE_APPROVAL_DEFAULT", "Answer when the approver cannot give one, allow or deny", oneOf("allow", "deny"))
+	unhealthyRules          = newSetting("unhealthy-rules", "CIVO_NODE_UNHEALTHY_RULES", "JSON array of CEL rules that find nodes unhealthy", nil)
+	httpAddress             = newSetting("http-address", "CIVO_NODE_AGENT_HTTP_ADDRESS", "Address the metrics, status and probe endpoints listen on", nil)
+	logLevel                = newSetting("log-level", "CIVO_NODE_AGENT_LOG_LEVEL", "Log level, debug, info, warn or error (default info)", oneOf("debug", "info", "warn", "error"))
+	logFormat               = newSetting("log-format", "CIVO_NODE_AGENT_LOG_FORMAT", "Log format, json or text (default json)", oneOf("json", "text"))
+)
+
+// registerFlags adds a flag for every setting to the flag set.
+func registerFlags(fs *flag.FlagSet) {
+	for _, s := range settings {
+		fs.StringVar(&s.flagValue, s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+}
+
+// resolveSettings sets the value of every setting from its flag, if it was given, or from its environment variable,
+// and returns an error for every invalid value.
+func resolveSettings(fs *flag.FlagSet) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	var errs []error
+	for _, s := range settings {
+		source := "environment variable " + s.env
+		s.value = strings.TrimSpace(os.Getenv(s.env))
+		if given[s.flag] {
+			source = "flag --" + s.flag
+			s.value = strings.TrimSpace(s.flagValue)
+		}
+		if s.value == "" || s.validate == nil {
+			continue
+		}
+		if err := s.validate(s.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s is invalid: %w", source, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// usage prints the help of node-agent.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: node-agent [flags] [command]\n\n")
+	fmt.Fprintf(out, "Watches the nodes of a Civo node pool and remediates the unhealthy ones. Commands:\n\n")
+	fmt.Fprintf(out, "  check           Evaluate the node pool once and print what would be done with each node\n")
+	fmt.Fprintf(out, "  explain <node>  Print why a node is or is not considered unhealthy\n")
+	fmt.Fprintf(out, "  reboot <node>   Remediate a node after checking that it is safe\n\n")
+	fmt.Fprintf(out, "The flags below must be given before the command, e.g. node-agent --cluster-id <id> check.\n")
+	fmt.Fprintf(out, "The flags of a command, which follow it, are printed by node-agent <command> -h.\n")
+	fmt.Fprintf(out, "Every flag can also be set with the environment variable in its description.\n")
+	fmt.Fprintf(out, "Flags take precedence over environment variables, which take precedence over the defaults.\n\n")
+	fmt.Fprintf(out, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+// newLogHandler returns the log handler for the log level and format settings, which default to the given ones.
+func newLogHandler(out io.Writer, defaultLevel slog.Level, defaultFormat string) slog.Handler {
+	level := defaultLevel
+	if logLevel.value != "" {
+		// The value is validated by resolveSettings.
+		_ = level.UnmarshalText([]byte(logLevel.value))
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	if cmp.Or(logFormat.value, defaultFormat) == "text" {
+		return slog.NewTextHandler(out, opts)
+	}
+	return slog.NewJSONHandler(out, opts)
+}
+
+func atLeast(min int) func(string) error {
+	return func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", s)
+		}
+		if n < min {
+			return fmt.Errorf("%d is less than %d", n, min)
+		}
+		return nil
+	}
+}
+
+func isBool(s string) error {
+	if _, err := strconv.ParseBool(s); err != nil {
+		return fmt.Errorf("%q is not true or false", s)
+	}
+	return nil
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(s string) error {
+		if !slices.Contains(values, s) {
+			return fmt.Errorf("%q is not one of %s", s, strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+// listOf returns a validation of comma-separated values, each of which must be valid.
+func listOf(validate func(string) error) func(string) error {
+	return func(s string) error {
+		var errs []error
+		for _, value := range strings.Split(s, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				errs = append(errs, validate(value))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func isURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("%q is not a URL: %w", s, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", s)
+	}
+	return nil
+}
+
+func isQualifiedName(s string) error {
+	if errs := validation.IsQualifiedName(s); len(errs) > 0 {
+		return fmt.Errorf("%q is invalid: %s", s, strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestResolveSettings(t *testing.T) {
+	type test struct {
+		name    string
+		env     map[string]string
+		args    []string
+		want    map[*setting]string
+		wantErr bool
+	}
+
+	tests := []test{
+		{
+			name: "Uses environment variables when flags are not given",
+			env: map[string]string{
+				"CIVO_CLUSTER_ID":                      "env-cluster",
+				"CIVO_NODE_REBOOT_TIME_WINDOW_MINUTES": " 30 ",
+			},
+			want: map[*setting]string{
+				clusterID:               "env-cluster",
+				rebootTimeWindowMinutes: "30",
+				nodePoolID:              "",
+			},
+		},
+		{
+			name: "Flags take precedence over environment variables",
+			env: map[string]string{
+				"CIVO_CLUSTER_ID": "env-cluster",
+			},
+			args: []string{"--cluster-id", "flag-cluster", "--interval", "5"},
+			want: map[*setting]string{
+				clusterID:         "flag-cluster",
+				reconcileInterval: "5",
+			},
+		},
+		{
+			name: "Empty flag overrides environment variable",
+			env: map[string]string{
+				"CIVO_CLUSTER_ID": "env-cluster",
+			},
+			args: []string{"--cluster-id="},
+			want: map[*setting]string{
+				clusterID: "",
+			},
+		},
+		{
+			name:    "Returns error when flag is invalid",
+			args:    []string{"--desired-gpu-count", "eight"},
+			wantErr: true,
+		},
+		{
+			name: "Returns error when environment variable is invalid",
+			env: map[string]string{
+				"CIVO_NODE_AGENT_OPT_IN": "maybe",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Returns error when value is not one of the choices",
+			args:    []string{"--log-level", "trace"},
+			wantErr: true,
+		},
+		{
+			name:    "Returns error when value is one of the choices in another case",
+			args:    []string{"--remediation", "Hard-Reboot"},
+			wantErr: true,
+		},
+		{
+			name: "Accepts valid lists and URLs",
+			args: []string{
+				"--webhook-events", "unhealthy, failed",
+				"--maintenance-taint-keys", "example.com/maintenance,upgrade",
+				"--capacity-check-resources", "nvidia.com/gpu",
+				"--webhook-url", "https://hooks.example.com/node-agent",
+			},
+			want: map[*setting]string{
+				webhookEvents: "unhealthy, failed",
+			},
+		},
+		{
+			name:    "Returns error when an event is unknown",
+			args:    []string{"--webhook-events", "unhealthy,rebooted"},
+			wantErr: true,
+		},
+		{
+			name:    "Returns error when a taint key is invalid",
+			args:    []string{"--maintenance-taint-keys", "example.com/under maintenance"},
+			wantErr: true,
+		},
+		{
+			name: "Returns error when a URL is invalid",
+			env: map[string]string{
+				"CIVO_NODE_APPROVAL_URL": "approver.example.com",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, s := range settings {
+				t.Setenv(s.env, test.env[s.env])
+			}
+			fs := flag.NewFlagSet("node-agent", flag.ContinueOnError)
+			registerFlags(fs)
+			if err := fs.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := resolveSettings(fs)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, test.wantErr)
+			}
+			for s, want := range test.want {
+				if s.value != want {
+					t.Errorf("%s = %q, want %q", s.flag, s.value, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/civo/node-agent/pkg/watcher"
+)
+
+// runExplain explains why node-agent considers a node healthy or unhealthy and returns the exit code.
+func runExplain(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	output := fs.String("output", "text", "Output format, either text or json")
+	kubeconfigPath := fs.String("kubeconfig", defaultKubeconfigPath(), "Path to the kubeconfig, the in-cluster config is used when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: node-agent explain [flags] <node>\n\n")
+		fmt.Fprintf(fs.Output(), "Prints every check of the node, the values node-agent decided on and the rule that produced its decision.\n\n")
+		fs.PrintDefaults()
+	}
+	nodeName, err := parseNodeArgs(fs, args)
+	if err != nil {
+		return 1
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "invalid output %q, must be text or json\n", *output)
+		return 1
+	}
+
+	w, err := newCLIWatcher(ctx, *kubeconfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create watcher: %v\n", err)
+		return 1
+	}
+	e, err := w.Explain(ctx, nodeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to explain node: %v\n", err)
+		return 1
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(e)
+	} else {
+		err = printExplanation(os.Stdout, e)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to print explanation: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printExplanation(out io.Writer, e *watcher.Explanation) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Node:\t%s\n", e.Name)
+	fmt.Fprintf(tw, "Instance:\t%s\n", orDash(e.InstanceID))
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(&e.CreatedAt))
+	fmt.Fprintf(tw, "Allocatable GPUs:\t%s (capacity %s, desired %d)\n", orDash(e.AllocatableGPUs), orDash(e.CapacityGPUs), e.DesiredGPUs)
+	fmt.Fprintf(tw, "Ready transition:\t%s\n", formatTime(e.LastTransitionTime))
+	fmt.Fprintf(tw, "Last remediation:\t%s\n", formatTime(e.LastRemediation))
+	fmt.Fprintf(tw, "Threshold time:\t%s (reboot time window of %s)\n", formatTime(&e.ThresholdTime), e.RebootTimeWindow)
+	fmt.Fprintf(tw, "Remediation paused:\t%s\n", orDash(e.PauseReason))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nConditions:")
+	tw = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TYPE\tSTATUS\tLAST TRANSITION\tREASON")
+	for _, c := range e.Conditions {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", c.Type, c.Status, formatTime(&c.LastTransitionTime), orDash(c.Reason))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nChecks:")
+	tw = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  NAME\tHEALTHY\tSEVERITY\tREASON")
+	for _, c := range e.Checks {
+		fmt.Fprintf(tw, "  %s\t%t\t%s\t%s\n", c.Name, c.Healthy, c.Severity, orDash(c.Reason))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nDecision: %s, by the %s rule\n", e.Action, e.Rule)
+	if e.Reason != "" {
+		fmt.Fprintf(out, "Reason:   %s\n", e.Reason)
+	}
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -3,35 +3,63 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
+	_ "time/tzdata" // The container image has no timezone database, which maintenance windows need.
 
 	"github.com/civo/node-agent/pkg/watcher"
 )
 
 var versionInfo = flag.Bool("version", false, "Print the driver version")
 
-var (
-	apiURL                  = strings.TrimSpace(os.Getenv("CIVO_API_URL"))
-	apiKey                  = strings.TrimSpace(os.Getenv("CIVO_API_KEY"))
-	region                  = strings.TrimSpace(os.Getenv("CIVO_REGION"))
-	clusterID               = strings.TrimSpace(os.Getenv("CIVO_CLUSTER_ID"))
-	nodePoolID              = strings.TrimSpace(os.Getenv("CIVO_NODE_POOL_ID"))
-	nodeDesiredGPUCount     = strings.TrimSpace(os.Getenv("CIVO_NODE_DESIRED_GPU_COUNT"))
-	rebootTimeWindowMinutes = strings.TrimSpace(os.Getenv("CIVO_NODE_REBOOT_TIME_WINDOW_MINUTES"))
-)
+// newWatcher creates the watcher from the settings. The given options are applied last.
+func newWatcher(ctx context.Context, opts ...watcher.Option) (watcher.Watcher, error) {
+	return watcher.NewWatcher(ctx, apiURL.value, apiKey.value, region.value, clusterID.value, nodePoolID.value, append([]watcher.Option{
+		watcher.WithRebootTimeWindowMinutes(rebootTimeWindowMinutes.value),
+		watcher.WithDesiredGPUCount(nodeDesiredGPUCount.value),
+		watcher.WithNodeStartupGracePeriodMinutes(nodeStartupGracePeriod.value),
+		watcher.WithOptIn(optIn.value),
+		watcher.WithSkipCordonedNodes(skipCordonedNodes.value),
+		watcher.WithMaintenanceTaintKeys(maintenanceTaintKeys.value),
+		watcher.WithRebootWindows(rebootWindows.value),
+		watcher.WithRebootBlackouts(rebootBlackouts.value),
+		watcher.WithRebootWindowTimezone(rebootWindowTimezone.value),
+		watcher.WithRebootWindowOverrideMinutes(rebootWindowOverride.value),
+		watcher.WithMaxRebootsPerNode(maxRebootsPerNode.value),
+		watcher.WithMaxRebootsPeriodMinutes(maxRebootsPeriod.value),
+		watcher.WithVerificationTimeoutMinutes(verificationTimeout.value),
+		watcher.WithRemediation(remediation.value),
+		watcher.WithCapacityCheckResources(capacityCheckResources.value),
+		watcher.WithDevicePluginNamespace(devicePluginNamespace.value),
+		watcher.WithDevicePluginSelector(devicePluginSelector.value),
+		watcher.WithDevicePluginRestartPeriodMinutes(devicePluginRestart.value),
+		watcher.WithStuckPodCleanup(stuckPodCleanup.value),
+		watcher.WithStuckPodNamespaces(stuckPodNamespaces.value),
+		watcher.WithStuckPodSelector(stuckPodSelector.value),
+		watcher.WithWebhookURL(webhookURL.value),
+		watcher.WithWebhookTemplate(webhookTemplate.value),
+		watcher.WithWebhookEvents(webhookEvents.value),
+		watcher.WithWebhookRetries(webhookRetries.value),
+		watcher.WithApprovalURL(approvalURL.value),
+		watcher.WithApprovalTimeoutSeconds(approvalTimeout.value),
+		watcher.WithApprovalDefault(approvalDefault.value),
+		watcher.WithUnhealthyRules(unhealthyRules.value),
+		watcher.WithReconcileIntervalSeconds(reconcileInterval.value),
+		watcher.WithReconcileJitterSeconds(reconcileJitter.value),
+		watcher.WithReconcileTimeoutSeconds(reconcileTimeout.value),
+		watcher.WithKubernetesClientConfigPath(kubeconfigPath()),
+		watcher.WithHTTPAddress(httpAddress.value),
+	}, opts...)...)
+}
 
 func run(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	w, err := watcher.NewWatcher(ctx, apiURL, apiKey, region, clusterID, nodePoolID,
-		watcher.WithRebootTimeWindowMinutes(rebootTimeWindowMinutes),
-		watcher.WithDesiredGPUCount(nodeDesiredGPUCount),
-	)
+	w, err := newWatcher(ctx)
 	if err != nil {
 		return err
 	}
@@ -39,16 +67,36 @@ func run(ctx context.Context) error {
 }
 
 func main() {
+	flag.Usage = usage
+	registerFlags(flag.CommandLine)
 	flag.Parse()
 	if *versionInfo {
 		slog.Info("node-agent", "version", watcher.Version)
 		return
 	}
+	if err := resolveSettings(flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(2)
+	}
+
+	switch flag.Arg(0) {
+	case "":
+	case "check":
+		os.Exit(runCheck(context.Background(), flag.Args()[1:]))
+	case "reboot":
+		os.Exit(runReboot(context.Background(), flag.Args()[1:]))
+	case "explain":
+		os.Exit(runExplain(context.Background(), flag.Args()[1:]))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
 
-	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil).WithAttrs([]slog.Attr{
-		slog.String("clusterID", clusterID),
-		slog.String("region", region),
-		slog.String("nodePoolID", nodePoolID),
+	slog.SetDefault(slog.New(newLogHandler(os.Stdout, slog.LevelInfo, "json").WithAttrs([]slog.Attr{
+		slog.String("clusterID", clusterID.value),
+		slog.String("region", region.value),
+		slog.String("nodePoolID", nodePoolID.value),
 	})))
 
 	if err := run(context.Background()); err != nil {
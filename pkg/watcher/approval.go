@@ -0,0 +1,144 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Reasons of the events node-agent records on nodes about approvals.
+const (
+	eventReasonRemediationApproved = "NodeAgentRemediationApproved"
+	eventReasonRemediationDenied   = "NodeAgentRemediationDenied"
+)
+
+// ApprovalRequest is a remediation node-agent proposes to take.
+type ApprovalRequest struct {
+	ClusterID  string `json:"clusterID"`
+	NodePoolID string `json:"nodePoolID"`
+	Node       string `json:"node"`
+	InstanceID string `json:"instanceID,omitempty"`
+	Action     string `json:"action"`
+	Reason     string `json:"reason"`
+	// Attempt is the number of remediations of the node within the max reboots period, including this one.
+	Attempt int `json:"attempt"`
+}
+
+// Approval is the answer to an ApprovalRequest.
+type Approval struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Approver approves or denies remediations before they are taken.
+type Approver interface {
+	Approve(ctx context.Context, req ApprovalRequest) (Approval, error)
+}
+
+type webhookApprover struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookApprover returns an Approver that POSTs the JSON encoded ApprovalRequest to the URL,
+// and expects a 2xx response with a JSON encoded Approval, e.g. {"approved": true}.
+// Requests that take longer than the timeout fail.
+func NewWebhookApprover(url string, timeout time.Duration) Approver {
+	return &webhookApprover{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (a *webhookApprover) Approve(ctx context.Context, approvalReq ApprovalRequest) (Approval, error) {
+	payload, err := json.Marshal(approvalReq)
+	if err != nil {
+		return Approval{}, fmt.Errorf("failed to encode approval request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(payload))
+	if err != nil {
+		return Approval{}, fmt.Errorf("failed to create approval request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return Approval{}, fmt.Errorf("failed to send approval request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Approval{}, fmt.Errorf("approver responded with status %d", resp.StatusCode)
+	}
+
+	var approval Approval
+	if err := json.NewDecoder(resp.Body).Decode(&approval); err != nil {
+		return Approval{}, fmt.Errorf("failed to decode approval response: %w", err)
+	}
+	return approval, nil
+}
+
+// approve asks the approver whether the node may be remediated. When the approver cannot give an answer,
+// e.g. because it timed out, the default approval applies. The answer is logged and recorded as an event on the node.
+// Remediations are always approved when no approver is set. After a denial, the approver is not asked again
+// about the node within the reboot time window.
+func (w *watcher) approve(ctx context.Context, node *corev1.Node, reason string) Approval {
+	if w.approver == nil {
+		return Approval{Approved: true}
+	}
+
+	name := node.GetName()
+	if denial, ok := w.recentDenial(name, time.Now()); ok {
+		slog.Info("Remediation was denied recently, not asking the approver again", "node", name, "reason", denial.Reason)
+		return denial
+	}
+
+	approval, err := w.approver.Approve(ctx, ApprovalRequest{
+		ClusterID:  w.clusterID,
+		NodePoolID: w.nodePoolID,
+		Node:       name,
+		InstanceID: instanceIDFromProviderID(node.Spec.ProviderID),
+		Action:     w.remediator.Name(),
+		Reason:     reason,
+		Attempt:    len(w.rebootHistoryOf(name)) + 1,
+	})
+	if err != nil {
+		slog.Error("Failed to get approval for remediation, applying the default", "node", name, "approved", w.approvalDefault, "error", err)
+		approval = Approval{
+			Approved: w.approvalDefault,
+			Reason:   fmt.Sprintf("no answer from approver (%v), approved by default: %t", err, w.approvalDefault),
+		}
+	}
+	w.recordApproval(name, approval, time.Now())
+
+	if approval.Approved {
+		slog.Info("Remediation is approved", "node", name, "reason", approval.Reason)
+		w.eventRecorder.Eventf(nodeReference(name), corev1.EventTypeNormal, eventReasonRemediationApproved,
+			"Remediation with %s was approved: %s", w.remediator.Name(), approval.Reason)
+	} else {
+		slog.Info("Remediation is denied", "node", name, "reason", approval.Reason)
+		w.eventRecorder.Eventf(nodeReference(name), corev1.EventTypeWarning, eventReasonRemediationDenied,
+			"Remediation with %s was denied: %s", w.remediator.Name(), approval.Reason)
+	}
+	return approval
+}
+
+// recentDenial returns the latest answer about the node when it was a denial within the reboot time window.
+func (w *watcher) recentDenial(nodeName string, now time.Time) (Approval, bool) {
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+
+	status, ok := w.nodeStatuses[nodeName]
+	if !ok || status.approval == nil || status.approval.Approved {
+		return Approval{}, false
+	}
+	if now.Sub(status.approvedAt) >= w.rebootTimeWindowMinutes*time.Minute {
+		return Approval{}, false
+	}
+	return *status.approval, true
+}
@@ -0,0 +1,272 @@
+package watcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRemediateNodeApproval(t *testing.T) {
+	type test struct {
+		name            string
+		handler         http.HandlerFunc
+		approvalDefault string
+		wantRemediated  bool
+		wantEventReason string
+	}
+
+	respond := func(body string) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			var approvalReq ApprovalRequest
+			if err := json.NewDecoder(req.Body).Decode(&approvalReq); err != nil || approvalReq.Node != "node-01" || approvalReq.Action != "counting" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			rw.Write([]byte(body))
+		}
+	}
+
+	tests := []test{
+		{
+			name:            "Remediates when the approver approves",
+			handler:         respond(`{"approved": true, "reason": "on-call approved"}`),
+			approvalDefault: "deny",
+			wantRemediated:  true,
+			wantEventReason: eventReasonRemediationApproved,
+		},
+		{
+			name:            "Does not remediate when the approver denies",
+			handler:         respond(`{"approved": false, "reason": "training run in progress"}`),
+			approvalDefault: "allow",
+			wantRemediated:  false,
+			wantEventReason: eventReasonRemediationDenied,
+		},
+		{
+			name: "Does not remediate when the approver fails and the default is deny",
+			handler: func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(http.StatusInternalServerError)
+			},
+			approvalDefault: "deny",
+			wantRemediated:  false,
+			wantEventReason: eventReasonRemediationDenied,
+		},
+		{
+			name: "Remediates when the approver times out and the default is allow",
+			handler: func(rw http.ResponseWriter, _ *http.Request) {
+				time.Sleep(2 * time.Second)
+			},
+			approvalDefault: "allow",
+			wantRemediated:  true,
+			wantEventReason: eventReasonRemediationApproved,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(test.handler)
+			defer srv.Close()
+
+			remediator := &countingRemediator{}
+			recorder := record.NewFakeRecorder(10)
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(&FakeClient{}),
+				WithRemediator(remediator),
+				WithEventRecorder(recorder),
+				WithApprovalURL(srv.URL),
+				WithApprovalTimeoutSeconds("1"),
+				WithApprovalDefault(test.approvalDefault),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := w.(*watcher)
+			obj.transition("node-01", nodeEventObservedUnhealthy, "node is not ready", time.Now())
+			if _, err := obj.remediateNode(t.Context(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-01"}}, "node is not ready"); err != nil {
+				t.Fatal(err)
+			}
+
+			if remediated := remediator.calls > 0; remediated != test.wantRemediated {
+				t.Errorf("remediated = %v, want %v", remediated, test.wantRemediated)
+			}
+			if event := <-recorder.Events; !strings.Contains(event, test.wantEventReason) {
+				t.Errorf("event = %q, want reason %s", event, test.wantEventReason)
+			}
+
+			wantState := nodeStateSuspect
+			if test.wantRemediated {
+				wantState = nodeStateVerifying
+			}
+			if state, _ := obj.nodeStateOf("node-01"); state != wantState {
+				t.Errorf("state = %s, want %s", state, wantState)
+			}
+			if obj.nodeStatuses["node-01"].approval == nil {
+				t.Error("approval was not recorded")
+			}
+		})
+	}
+}
+
+func TestRemediateNodeDoesNotAskAgainAfterDenial(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		rw.Write([]byte(`{"approved": false, "reason": "training run in progress"}`))
+	}))
+	defer srv.Close()
+
+	remediator := &countingRemediator{}
+	recorder := record.NewFakeRecorder(10)
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(&FakeClient{}),
+		WithRemediator(remediator),
+		WithEventRecorder(recorder),
+		WithApprovalURL(srv.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-01"}}
+	obj.transition(node.GetName(), nodeEventObservedUnhealthy, "node is not ready", time.Now())
+	for range 3 {
+		if _, err := obj.remediateNode(t.Context(), node, "node is not ready"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("approval requests = %d, want 1", got)
+	}
+	if got := len(recorder.Events); got != 1 {
+		t.Errorf("events = %d, want 1", got)
+	}
+	if remediator.calls != 0 {
+		t.Errorf("remediations = %d, want 0", remediator.calls)
+	}
+
+	obj.nodeStatuses[node.GetName()].approvedAt = time.Now().Add(-obj.rebootTimeWindowMinutes * time.Minute)
+	if _, err := obj.remediateNode(t.Context(), node, "node is not ready"); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("approval requests after the reboot time window = %d, want 2", got)
+	}
+}
+
+func TestRemediateNodeDoesNotAskApprovalForSkippedRemediation(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		rw.Write([]byte(`{"approved": true}`))
+	}))
+	defer srv.Close()
+
+	civoClient := &FakeClient{
+		FindKubernetesClusterInstanceFunc: func(_, search string) (*civogo.Instance, error) {
+			return &civogo.Instance{ID: "instance-01", Hostname: search, Status: instanceStatusRebooting}, nil
+		},
+	}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(civoClient),
+		WithApprovalURL(srv.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	result, err := obj.remediateNode(t.Context(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-01"}}, "node is not ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Skipped {
+		t.Error("remediation of rebooting instance was issued")
+	}
+	if state, seen := obj.nodeStateOf("node-01"); seen {
+		t.Errorf("state = %s, want the skipped remediation to leave it as is", state)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("approval requests = %d, want 0", got)
+	}
+}
+
+func TestRunDoesNotRemediateAgainAfterDenial(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		rw.Write([]byte(`{"approved": false, "reason": "training run in progress"}`))
+	}))
+	defer srv.Close()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+				},
+			},
+		},
+	}
+	remediator := &countingRemediator{}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(newFakeClient()),
+		WithRemediator(remediator),
+		WithEventRecorder(record.NewFakeRecorder(10)),
+		WithApprovalURL(srv.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	for range 5 {
+		if err := obj.run(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("approval requests = %d, want 1", got)
+	}
+	if remediator.calls != 0 {
+		t.Errorf("remediations = %d, want 0", remediator.calls)
+	}
+	status := obj.nodeStatuses["node-01"]
+	if status.state != nodeStateSuspect {
+		t.Errorf("state = %s, want %s", status.state, nodeStateSuspect)
+	}
+	if len(status.transitions) != 1 {
+		t.Errorf("transitions = %+v, want only Healthy -> Suspect", status.transitions)
+	}
+	if status.decision.rule != ruleApprovalDenied {
+		t.Errorf("decision rule = %s, want %s", status.decision.rule, ruleApprovalDenied)
+	}
+}
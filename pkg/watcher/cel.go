@@ -0,0 +1,165 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// unhealthyRule is a custom rule that finds a node unhealthy when its CEL expression evaluates to true.
+type unhealthyRule struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Severity   string `json:"severity,omitempty"`
+}
+
+// parseUnhealthyRules parses a JSON array of rules, e.g.
+// [{"name": "not-ready-5m", "expression": "notReadyFor > duration('5m')", "severity": "critical"}],
+// and compiles each of them into a HealthCheck.
+func parseUnhealthyRules(s string) ([]HealthCheck, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var rules []unhealthyRule
+	if err := json.Unmarshal([]byte(s), &rules); err != nil {
+		return nil, fmt.Errorf("invalid unhealthy rules: %w", err)
+	}
+
+	checks := make([]HealthCheck, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("invalid unhealthy rule at index %d: name is empty", i)
+		}
+		severity, err := parseSeverity(rule.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unhealthy rule %q: %w", rule.Name, err)
+		}
+		check, err := NewCELCheck(rule.Name, rule.Expression, severity)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// parseSeverity parses the name of a severity. It defaults to critical.
+func parseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "critical":
+		return SeverityCritical, nil
+	case "warning":
+		return SeverityWarning, nil
+	default:
+		return 0, fmt.Errorf("unknown severity %q, must be critical or warning", s)
+	}
+}
+
+type celCheck struct {
+	name       string
+	expression string
+	severity   Severity
+	program    cel.Program
+}
+
+// NewCELCheck returns a HealthCheck that finds nodes unhealthy with the given severity when
+// the CEL expression evaluates to true. The expression is compiled and type-checked immediately.
+//
+// The expression can use the following variables:
+//   - node: the Node object, e.g. node.metadata.name or node.spec.unschedulable
+//   - notReadyFor: how long the node has not been Ready, or 0s when it is Ready
+//   - allocatable: the allocatable resources of the node as integers, e.g. allocatable['nvidia.com/gpu']
+//   - capacity: the capacity resources of the node as integers, e.g. capacity['nvidia.com/gpu']
+//   - now: the current time
+func NewCELCheck(name, expression string, severity Severity) (HealthCheck, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("node", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("notReadyFor", cel.DurationType),
+		cel.Variable("allocatable", cel.MapType(cel.StringType, cel.IntType)),
+		cel.Variable("capacity", cel.MapType(cel.StringType, cel.IntType)),
+		cel.Variable("now", cel.TimestampType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression of unhealthy rule %q: %w", name, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("invalid expression of unhealthy rule %q: must evaluate to bool, but evaluates to %s", name, ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression of unhealthy rule %q: %w", name, err)
+	}
+	return &celCheck{
+		name:       name,
+		expression: expression,
+		severity:   severity,
+		program:    program,
+	}, nil
+}
+
+func (c *celCheck) Name() string {
+	return "CEL:" + c.name
+}
+
+// Check evaluates the expression against the node. When the expression cannot be evaluated,
+// e.g. because it accesses a field the node does not have, the node is reported with a warning
+// rather than remediated.
+func (c *celCheck) Check(ctx context.Context, node *corev1.Node) Verdict {
+	vars, err := celVariables(node, time.Now())
+	if err != nil {
+		return Unhealthy(SeverityWarning, fmt.Sprintf("failed to convert node: %v", err))
+	}
+
+	out, _, err := c.program.ContextEval(ctx, vars)
+	if err != nil {
+		return Unhealthy(SeverityWarning, fmt.Sprintf("failed to evaluate %q: %v", c.expression, err))
+	}
+	if unhealthy, ok := out.Value().(bool); ok && unhealthy {
+		return Unhealthy(c.severity, fmt.Sprintf("%q is true", c.expression))
+	}
+	return Healthy(fmt.Sprintf("%q is false", c.expression))
+}
+
+// celVariables returns the variables CEL expressions are evaluated with.
+func celVariables(node *corev1.Node, now time.Time) (map[string]any, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(node)
+	if err != nil {
+		return nil, err
+	}
+
+	var notReadyFor time.Duration
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+			notReadyFor = now.Sub(cond.LastTransitionTime.Time)
+		}
+	}
+
+	return map[string]any{
+		"node":        obj,
+		"notReadyFor": notReadyFor,
+		"allocatable": resourceValues(node.Status.Allocatable),
+		"capacity":    resourceValues(node.Status.Capacity),
+		"now":         now,
+	}, nil
+}
+
+func resourceValues(resources corev1.ResourceList) map[string]int64 {
+	values := make(map[string]int64, len(resources))
+	for name, quantity := range resources {
+		values[string(name)] = quantity.Value()
+	}
+	return values
+}
@@ -0,0 +1,205 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewCELCheck(t *testing.T) {
+	type test struct {
+		name       string
+		expression string
+		wantErr    bool
+	}
+
+	tests := []test{
+		{
+			name:       "Compiles expression using node fields",
+			expression: `node.metadata.name == "node-01"`,
+		},
+		{
+			name:       "Compiles expression using computed fields",
+			expression: `notReadyFor > duration("5m") && allocatable["nvidia.com/gpu"] < capacity["nvidia.com/gpu"]`,
+		},
+		{
+			name:       "Returns error when expression does not parse",
+			expression: `notReadyFor >`,
+			wantErr:    true,
+		},
+		{
+			name:       "Returns error when expression uses an unknown variable",
+			expression: `unknown == 1`,
+			wantErr:    true,
+		},
+		{
+			name:       "Returns error when expression does not evaluate to bool",
+			expression: `notReadyFor`,
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewCELCheck("rule", test.expression, SeverityCritical)
+			if (err != nil) != test.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCELCheck(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				"gpu": "true",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+				},
+			},
+			Allocatable: corev1.ResourceList{
+				gpuResourceName: resource.MustParse("7"),
+			},
+			Capacity: corev1.ResourceList{
+				gpuResourceName: resource.MustParse("8"),
+			},
+		},
+	}
+
+	type test struct {
+		name        string
+		expression  string
+		severity    Severity
+		wantHealthy bool
+		wantSev     Severity
+	}
+
+	tests := []test{
+		{
+			name:        "Returns unhealthy when node has not been ready for longer than the threshold",
+			expression:  `notReadyFor > duration("5m")`,
+			severity:    SeverityCritical,
+			wantHealthy: false,
+			wantSev:     SeverityCritical,
+		},
+		{
+			name:        "Returns healthy when node has not been ready for shorter than the threshold",
+			expression:  `notReadyFor > duration("15m")`,
+			severity:    SeverityCritical,
+			wantHealthy: true,
+			wantSev:     SeverityInfo,
+		},
+		{
+			name:        "Returns unhealthy with the rule severity when resources are missing",
+			expression:  `allocatable["nvidia.com/gpu"] < capacity["nvidia.com/gpu"]`,
+			severity:    SeverityWarning,
+			wantHealthy: false,
+			wantSev:     SeverityWarning,
+		},
+		{
+			name:        "Returns healthy when node fields do not match",
+			expression:  `node.metadata.labels["gpu"] == "false"`,
+			severity:    SeverityCritical,
+			wantHealthy: true,
+			wantSev:     SeverityInfo,
+		},
+		{
+			name:        "Returns warning when expression fails to evaluate",
+			expression:  `allocatable["example.com/missing"] == 0`,
+			severity:    SeverityCritical,
+			wantHealthy: false,
+			wantSev:     SeverityWarning,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check, err := NewCELCheck("rule", test.expression, test.severity)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			verdict := check.Check(t.Context(), node)
+			if verdict.Healthy != test.wantHealthy {
+				t.Errorf("healthy = %v, want %v (%s)", verdict.Healthy, test.wantHealthy, verdict.Reason)
+			}
+			if verdict.Severity != test.wantSev {
+				t.Errorf("severity = %v, want %v", verdict.Severity, test.wantSev)
+			}
+		})
+	}
+}
+
+func TestWithUnhealthyRules(t *testing.T) {
+	type test struct {
+		name       string
+		rules      string
+		wantChecks int
+		wantErr    bool
+	}
+
+	tests := []test{
+		{
+			name:       "Adds no checks when rules are empty",
+			rules:      "",
+			wantChecks: 2,
+		},
+		{
+			name:       "Adds a check for each rule",
+			rules:      `[{"name": "a", "expression": "notReadyFor > duration('5m')"}, {"name": "b", "expression": "has(node.spec.unschedulable)", "severity": "warning"}]`,
+			wantChecks: 4,
+		},
+		{
+			name:    "Returns error when rules are not JSON",
+			rules:   `not-json`,
+			wantErr: true,
+		},
+		{
+			name:    "Returns error when a rule has no name",
+			rules:   `[{"expression": "true"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Returns error when a rule has an unknown severity",
+			rules:   `[{"name": "a", "expression": "true", "severity": "fatal"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Returns error when a rule does not compile",
+			rules:   `[{"name": "a", "expression": "notReadyFor"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(&FakeClient{}),
+				WithUnhealthyRules(test.rules),
+			)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if got := len(w.(*watcher).healthChecks); got != test.wantChecks {
+				t.Errorf("health checks = %d, want %d", got, test.wantChecks)
+			}
+		})
+	}
+}
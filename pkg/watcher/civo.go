@@ -0,0 +1,27 @@
+package watcher
+
+import "context"
+
+// callCivo makes a call to the Civo API, whose client takes no context, and returns when the call completes
+// or the context is done, whichever comes first, so that a hung call does not stall the reconcile.
+// A call that is given up on keeps running in the background until the Civo API responds.
+func callCivo[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := call()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
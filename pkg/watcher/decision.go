@@ -0,0 +1,176 @@
+package watcher
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// decisionAction is what node-agent decided to do with a node.
+type decisionAction string
+
+const (
+	decisionNone       decisionAction = "none"
+	decisionSkip       decisionAction = "skip"
+	decisionQueue      decisionAction = "queue"
+	decisionQuarantine decisionAction = "quarantine"
+	decisionRemediate  decisionAction = "remediate"
+)
+
+// Rules that produce a decision.
+const (
+	ruleHealthy                   = "healthy"
+	ruleExcluded                  = "excluded"
+	ruleQuarantined               = "quarantined"
+	ruleUnderMaintenance          = "under-maintenance"
+	ruleStartupGracePeriod        = "startup-grace-period"
+	ruleStatusChangedRecently     = "status-changed-recently"
+	ruleCooldown                  = "cooldown"
+	rulePaused                    = "paused"
+	ruleApprovalDenied            = "approval-denied"
+	ruleMaintenanceWindow         = "maintenance-window"
+	ruleMaintenanceWindowOverride = "maintenance-window-override"
+	ruleMaxReboots                = "max-reboots"
+	ruleUnhealthy                 = "unhealthy"
+)
+
+// decision is what node-agent decided to do with a node, and the rule that produced it.
+type decision struct {
+	action decisionAction
+	rule   string
+	reason string
+	// message is logged when the decision is made.
+	message string
+}
+
+// notifiesSkipped checks if a NotificationSkipped is sent for the decision. It is only sent when an unhealthy node
+// is held back to be remediated later, not when it is excluded from remediation altogether.
+func (d decision) notifiesSkipped() bool {
+	return d.action == decisionQueue || d.rule == ruleCooldown || d.rule == ruleStatusChangedRecently
+}
+
+// decide returns what to do with the node given its evaluation. The rules are applied in order,
+// and the first one that applies produces the decision.
+func (w *watcher) decide(node *corev1.Node, eval evaluation, now time.Time, pauseReason string) decision {
+	name := node.GetName()
+	thresholdTime := now.Add(-w.rebootTimeWindowMinutes * time.Minute)
+
+	if !eval.needsRemediation() {
+		return decision{
+			action:  decisionNone,
+			rule:    ruleHealthy,
+			reason:  eval.reason(),
+			message: "Node is healthy",
+		}
+	}
+	if managed, reason := isNodeManaged(node, w.optIn); !managed {
+		return decision{
+			action:  decisionSkip,
+			rule:    ruleExcluded,
+			reason:  reason,
+			message: "Skipping reboot because Node is excluded from remediation",
+		}
+	}
+	if isNodeQuarantined(node) {
+		return decision{
+			action:  decisionSkip,
+			rule:    ruleQuarantined,
+			reason:  node.GetAnnotations()[quarantinedReasonKey],
+			message: "Skipping reboot because Node is quarantined",
+		}
+	}
+	// Nodes an operator requested a reboot for are usually cordoned by that operator, so they are not skipped as under maintenance.
+	_, requested := pendingRebootRequest(node)
+	if underMaintenance, reason := isNodeUnderMaintenance(node, w.skipCordonedNodes, w.maintenanceTaintKeys); underMaintenance && !requested {
+		return decision{
+			action:  decisionSkip,
+			rule:    ruleUnderMaintenance,
+			reason:  reason,
+			message: "Skipping reboot because someone is working on the Node",
+		}
+	}
+	if isNodeInStartupGracePeriod(node, now, w.nodeStartupGracePeriod) {
+		return decision{
+			action: decisionSkip,
+			rule:   ruleStartupGracePeriod,
+			reason: fmt.Sprintf("node was created at %s, within the startup grace period of %s",
+				node.GetCreationTimestamp().String(), w.nodeStartupGracePeriod),
+			message: "Skipping reboot because Node was created recently and is still in its startup grace period",
+		}
+	}
+
+	// LTT:  LastTransitionTime of node.
+	// LRCT: LastRebootCmdTimes
+	// 60:   Threshold time (example)
+	// - LTT > 60 , LRCT < 60 dont reboot
+	// - LTT < 60 , LRCT < 60 dont reboot
+	// - LTT < 60 , LRCT > 60 dont reboot
+	// - LTT > 60, LRCT >. 60 reboot
+	if isReadyOrNotReadyStatusChangedAfter(node, thresholdTime) {
+		return decision{
+			action:  decisionSkip,
+			rule:    ruleStatusChangedRecently,
+			reason:  fmt.Sprintf("Ready/NotReady status changed after %s", thresholdTime.Format(time.RFC3339)),
+			message: "Skipping reboot because Ready/NotReady status was updated recently",
+		}
+	}
+	if w.isLastRebootCommandTimeAfter(name, thresholdTime) {
+		return decision{
+			action:  decisionSkip,
+			rule:    ruleCooldown,
+			reason:  fmt.Sprintf("node was remediated after %s", thresholdTime.Format(time.RFC3339)),
+			message: "Skipping reboot because Reboot command was executed recently",
+		}
+	}
+	if pauseReason != "" {
+		return decision{
+			action:  decisionSkip,
+			rule:    rulePaused,
+			reason:  pauseReason,
+			message: "Skipping reboot because remediation is paused",
+		}
+	}
+	if denial, ok := w.recentDenial(name, now); ok {
+		return decision{
+			action:  decisionSkip,
+			rule:    ruleApprovalDenied,
+			reason:  "remediation was denied: " + denial.Reason,
+			message: "Skipping reboot because remediation was denied recently",
+		}
+	}
+
+	d := decision{
+		action:  decisionRemediate,
+		rule:    ruleUnhealthy,
+		reason:  eval.reason(),
+		message: "Node is unhealthy, rebooting",
+	}
+	if allowed, reason := w.maintenance.allows(now); !allowed {
+		unhealthyFor := w.unhealthyDuration(node, now)
+		if w.rebootWindowOverride <= 0 || unhealthyFor < w.rebootWindowOverride {
+			return decision{
+				action: decisionQueue,
+				rule:   ruleMaintenanceWindow,
+				reason: fmt.Sprintf("%s, next window opens at %s, unhealthy for %s",
+					reason, w.maintenance.nextAllowed(now).Format(time.RFC3339), unhealthyFor.Round(time.Second)),
+				message: "Reboot is queued until the next maintenance window",
+			}
+		}
+		d.rule = ruleMaintenanceWindowOverride
+		d.reason = fmt.Sprintf("%s, but node has been unhealthy for %s, longer than the override of %s",
+			reason, unhealthyFor.Round(time.Second), w.rebootWindowOverride)
+		d.message = "Rebooting outside of maintenance windows because Node has been unhealthy for too long"
+	}
+	if w.maxRebootsPerNode > 0 {
+		if n := len(w.rebootHistoryOf(name)); n >= w.maxRebootsPerNode {
+			return decision{
+				action:  decisionQuarantine,
+				rule:    ruleMaxReboots,
+				reason:  fmt.Sprintf("node was rebooted %d times within %s and is still unhealthy", n, w.maxRebootsPeriod),
+				message: "Quarantining Node because it was rebooted too many times",
+			}
+		}
+	}
+	return d
+}
@@ -0,0 +1,140 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDecide(t *testing.T) {
+	now := time.Now()
+
+	newNode := func(ready corev1.ConditionStatus, transitionedAt time.Time) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "node-01",
+				CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             ready,
+						LastTransitionTime: metav1.NewTime(transitionedAt),
+					},
+				},
+			},
+		}
+	}
+	unhealthy := evaluation{results: []checkResult{{name: "Ready", verdict: Unhealthy(SeverityCritical, "node is not ready")}}}
+
+	type test struct {
+		name        string
+		opts        []Option
+		node        *corev1.Node
+		eval        evaluation
+		pauseReason string
+		beforeFunc  func(*watcher)
+		wantAction  decisionAction
+		wantRule    string
+	}
+
+	tests := []test{
+		{
+			name:       "Decides nothing when node is healthy",
+			node:       newNode(corev1.ConditionTrue, now.Add(-time.Hour)),
+			eval:       evaluation{results: []checkResult{{name: "Ready", verdict: Healthy("node is ready")}}},
+			wantAction: decisionNone,
+			wantRule:   ruleHealthy,
+		},
+		{
+			name:       "Decides to remediate when node has been unhealthy for longer than the reboot time window",
+			node:       newNode(corev1.ConditionFalse, now.Add(-time.Hour)),
+			eval:       unhealthy,
+			wantAction: decisionRemediate,
+			wantRule:   ruleUnhealthy,
+		},
+		{
+			name:       "Decides to skip when status changed recently",
+			node:       newNode(corev1.ConditionFalse, now.Add(-time.Minute)),
+			eval:       unhealthy,
+			wantAction: decisionSkip,
+			wantRule:   ruleStatusChangedRecently,
+		},
+		{
+			name: "Decides to skip when node was remediated recently",
+			node: newNode(corev1.ConditionFalse, now.Add(-time.Hour)),
+			eval: unhealthy,
+			beforeFunc: func(w *watcher) {
+				w.lastRebootCmdTimes.Store("node-01", now.Add(-time.Minute))
+			},
+			wantAction: decisionSkip,
+			wantRule:   ruleCooldown,
+		},
+		{
+			name:        "Decides to skip when remediation is paused",
+			node:        newNode(corev1.ConditionFalse, now.Add(-time.Hour)),
+			eval:        unhealthy,
+			pauseReason: "cluster status is UPGRADING",
+			wantAction:  decisionSkip,
+			wantRule:    rulePaused,
+		},
+		{
+			name: "Decides to skip when remediation was denied recently",
+			node: newNode(corev1.ConditionFalse, now.Add(-time.Hour)),
+			eval: unhealthy,
+			beforeFunc: func(w *watcher) {
+				w.transition("node-01", nodeEventObservedUnhealthy, "node is not ready", now)
+				w.recordApproval("node-01", Approval{Approved: false, Reason: "training run in progress"}, now.Add(-time.Minute))
+			},
+			wantAction: decisionSkip,
+			wantRule:   ruleApprovalDenied,
+		},
+		{
+			name:       "Decides to queue when outside of maintenance windows",
+			opts:       []Option{WithRebootBlackouts(now.Add(-time.Hour).Format(time.RFC3339) + "/" + now.Add(time.Hour).Format(time.RFC3339))},
+			node:       newNode(corev1.ConditionFalse, now.Add(-time.Hour)),
+			eval:       unhealthy,
+			wantAction: decisionQueue,
+			wantRule:   ruleMaintenanceWindow,
+		},
+		{
+			name: "Decides to quarantine when node was rebooted too many times",
+			opts: []Option{WithMaxRebootsPerNode("1")},
+			node: newNode(corev1.ConditionFalse, now.Add(-time.Hour)),
+			eval: unhealthy,
+			beforeFunc: func(w *watcher) {
+				w.recordReboot("node-01", now.Add(-2*time.Hour))
+			},
+			wantAction: decisionQuarantine,
+			wantRule:   ruleMaxReboots,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				append([]Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(&FakeClient{}),
+				}, test.opts...)...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			obj := w.(*watcher)
+			if test.beforeFunc != nil {
+				test.beforeFunc(obj)
+			}
+
+			d := obj.decide(test.node, test.eval, now, test.pauseReason)
+			if d.action != test.wantAction || d.rule != test.wantRule {
+				t.Errorf("decision = %s/%s, want %s/%s (%s)", d.action, d.rule, test.wantAction, test.wantRule, d.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultDevicePluginSelector is the label selector of the pods of the NVIDIA device plugin DaemonSet.
+const defaultDevicePluginSelector = "name=nvidia-device-plugin-ds"
+
+// crashLoopBackOffReason is the reason of a waiting container that keeps crashing.
+const crashLoopBackOffReason = "CrashLoopBackOff"
+
+// devicePluginPodsKey is the key of the context the device plugin pods of every node are prefetched in.
+type devicePluginPodsKey struct {
+	namespace string
+	selector  string
+}
+
+// devicePluginPods returns the device plugin pods scheduled on the node.
+func devicePluginPods(ctx context.Context, client kubernetes.Interface, namespace string, selector labels.Selector, nodeName string) ([]corev1.Pod, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device plugin pods, namespace: %s, nodeName: %s: %w", namespace, nodeName, err)
+	}
+	return devicePluginPodsByNode(pods.Items)[nodeName], nil
+}
+
+// devicePluginPodsByNode returns the pods that are not being deleted by the name of the node they are scheduled on.
+func devicePluginPodsByNode(pods []corev1.Pod) map[string][]corev1.Pod {
+	result := make(map[string][]corev1.Pod)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" && pod.DeletionTimestamp == nil {
+			result[pod.Spec.NodeName] = append(result[pod.Spec.NodeName], pod)
+		}
+	}
+	return result
+}
+
+// isPodCrashLooping checks if any container of the pod is waiting to be restarted after crashing.
+func isPodCrashLooping(pod *corev1.Pod) bool {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == crashLoopBackOffReason {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type devicePluginCheck struct {
+	client    kubernetes.Interface
+	namespace string
+	selector  labels.Selector
+}
+
+// NewDevicePluginCheck returns a HealthCheck that finds nodes critically unhealthy when the device plugin pod
+// matching the selector in the namespace is missing from the node or crash looping.
+func NewDevicePluginCheck(client kubernetes.Interface, namespace string, selector labels.Selector) HealthCheck {
+	return &devicePluginCheck{
+		client:    client,
+		namespace: namespace,
+		selector:  selector,
+	}
+}
+
+func (c *devicePluginCheck) Name() string {
+	return "DevicePlugin"
+}
+
+// prefetch lists the device plugin pods of all nodes at once.
+func (c *devicePluginCheck) prefetch(ctx context.Context) (context.Context, error) {
+	pods, err := c.client.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: c.selector.String(),
+	})
+	if err != nil {
+		return ctx, fmt.Errorf("failed to list device plugin pods, namespace: %s: %w", c.namespace, err)
+	}
+	return context.WithValue(ctx, c.key(), devicePluginPodsByNode(pods.Items)), nil
+}
+
+func (c *devicePluginCheck) key() devicePluginPodsKey {
+	return devicePluginPodsKey{namespace: c.namespace, selector: c.selector.String()}
+}
+
+// pods returns the device plugin pods of the node, which are only listed when they were not prefetched.
+func (c *devicePluginCheck) pods(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	if byNode, ok := ctx.Value(c.key()).(map[string][]corev1.Pod); ok {
+		return byNode[nodeName], nil
+	}
+	return devicePluginPods(ctx, c.client, c.namespace, c.selector, nodeName)
+}
+
+func (c *devicePluginCheck) Check(ctx context.Context, node *corev1.Node) Verdict {
+	pods, err := c.pods(ctx, node.GetName())
+	if err != nil {
+		return Unhealthy(SeverityWarning, err.Error())
+	}
+	if len(pods) == 0 {
+		return Unhealthy(SeverityCritical, "device plugin pod is missing")
+	}
+	for _, pod := range pods {
+		if isPodCrashLooping(&pod) {
+			return Unhealthy(SeverityCritical, fmt.Sprintf("device plugin pod %s is crash looping", pod.GetName()))
+		}
+	}
+	return Healthy("device plugin pod is running")
+}
+
+// devicePluginRemediator restarts crash looping device plugin pods before escalating to another Remediator.
+type devicePluginRemediator struct {
+	client    kubernetes.Interface
+	namespace string
+	selector  labels.Selector
+	next      Remediator
+	period    time.Duration
+
+	mu       sync.Mutex
+	restarts map[string]time.Time
+}
+
+// NewDevicePluginRemediator returns a Remediator that deletes the crash looping device plugin pods on the node,
+// which is much cheaper than a reboot, so that their DaemonSet recreates them. When the plugin pods of the node
+// are not crash looping, or have already been deleted within the period, it escalates to the next Remediator.
+func NewDevicePluginRemediator(client kubernetes.Interface, namespace string, selector labels.Selector, period time.Duration, next Remediator) Remediator {
+	return &devicePluginRemediator{
+		client:    client,
+		namespace: namespace,
+		selector:  selector,
+		next:      next,
+		period:    period,
+		restarts:  make(map[string]time.Time),
+	}
+}
+
+func (r *devicePluginRemediator) Name() string {
+	return r.next.Name()
+}
+
+func (r *devicePluginRemediator) skipReason(ctx context.Context, node *corev1.Node) (string, error) {
+	name := node.GetName()
+
+	r.mu.Lock()
+	restartedAt, restarted := r.restarts[name]
+	r.mu.Unlock()
+	if !restarted || time.Since(restartedAt) >= r.period {
+		pods, err := devicePluginPods(ctx, r.client, r.namespace, r.selector, name)
+		if err != nil {
+			return "", err
+		}
+		for _, pod := range pods {
+			if isPodCrashLooping(&pod) {
+				return "", nil
+			}
+		}
+	}
+	return remediationSkipReason(ctx, r.next, node)
+}
+
+func (r *devicePluginRemediator) Remediate(ctx context.Context, node *corev1.Node) (RemediationResult, error) {
+	name := node.GetName()
+	now := time.Now()
+
+	r.mu.Lock()
+	restartedAt, restarted := r.restarts[name]
+	r.mu.Unlock()
+	if restarted && now.Sub(restartedAt) < r.period {
+		slog.Info("Escalating remediation because the device plugin was already restarted",
+			"node", name, "restartedAt", restartedAt, "remediation", r.next.Name())
+		return r.next.Remediate(ctx, node)
+	}
+
+	pods, err := devicePluginPods(ctx, r.client, r.namespace, r.selector, name)
+	if err != nil {
+		return RemediationResult{}, err
+	}
+	var deleted []string
+	for _, pod := range pods {
+		if !isPodCrashLooping(&pod) {
+			continue
+		}
+		if err := r.client.CoreV1().Pods(pod.GetNamespace()).Delete(ctx, pod.GetName(), metav1.DeleteOptions{}); err != nil {
+			return RemediationResult{}, fmt.Errorf("failed to delete device plugin pod, namespace: %s, podName: %s: %w", pod.GetNamespace(), pod.GetName(), err)
+		}
+		slog.Info("Device plugin pod is deleted", "node", name, "namespace", pod.GetNamespace(), "pod", pod.GetName())
+		deleted = append(deleted, pod.GetName())
+	}
+	if len(deleted) == 0 {
+		return r.next.Remediate(ctx, node)
+	}
+
+	r.mu.Lock()
+	r.restarts[name] = now
+	r.mu.Unlock()
+	return RemediationResult{
+		Action:      "restart device plugin",
+		Reason:      fmt.Sprintf("device plugin pod %s is crash looping", strings.Join(deleted, ", ")),
+		Lightweight: true,
+	}, nil
+}
@@ -0,0 +1,256 @@
+package watcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// countingRemediator is a Remediator that counts how many times it was called.
+type countingRemediator struct {
+	calls int
+}
+
+func (*countingRemediator) Name() string {
+	return "counting"
+}
+
+func (r *countingRemediator) Remediate(context.Context, *corev1.Node) (RemediationResult, error) {
+	r.calls++
+	return RemediationResult{Action: "count"}, nil
+}
+
+func newDevicePluginPod(name, nodeName string, crashLooping bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			Labels: map[string]string{
+				"name": "nvidia-device-plugin-ds",
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "nvidia-device-plugin-ctr",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+	if crashLooping {
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: crashLoopBackOffReason},
+		}
+	}
+	return pod
+}
+
+func TestDevicePluginCheck(t *testing.T) {
+	type test struct {
+		name         string
+		pods         []runtime.Object
+		wantHealthy  bool
+		wantSeverity Severity
+	}
+
+	tests := []test{
+		{
+			name:         "Returns healthy when device plugin pod is running",
+			pods:         []runtime.Object{newDevicePluginPod("plugin-01", "node-01", false)},
+			wantHealthy:  true,
+			wantSeverity: SeverityInfo,
+		},
+		{
+			name:         "Returns unhealthy when device plugin pod is crash looping",
+			pods:         []runtime.Object{newDevicePluginPod("plugin-01", "node-01", true)},
+			wantHealthy:  false,
+			wantSeverity: SeverityCritical,
+		},
+		{
+			name:         "Returns unhealthy when device plugin pod is only on another node",
+			pods:         []runtime.Object{newDevicePluginPod("plugin-02", "node-02", false)},
+			wantHealthy:  false,
+			wantSeverity: SeverityCritical,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selector, err := labels.Parse(defaultDevicePluginSelector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			check := NewDevicePluginCheck(fake.NewSimpleClientset(test.pods...), "kube-system", selector)
+
+			verdict := check.Check(t.Context(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-01"}})
+			if verdict.Healthy != test.wantHealthy {
+				t.Errorf("healthy = %v, want %v (%s)", verdict.Healthy, test.wantHealthy, verdict.Reason)
+			}
+			if verdict.Severity != test.wantSeverity {
+				t.Errorf("severity = %v, want %v", verdict.Severity, test.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestDevicePluginRemediator(t *testing.T) {
+	type test struct {
+		name            string
+		pod             *corev1.Pod
+		restartedAt     time.Time
+		wantNextCalls   int
+		wantPodDeleted  bool
+		wantLightweight bool
+	}
+
+	tests := []test{
+		{
+			name:            "Deletes crash looping device plugin pod instead of escalating",
+			pod:             newDevicePluginPod("plugin-01", "node-01", true),
+			wantPodDeleted:  true,
+			wantLightweight: true,
+		},
+		{
+			name:          "Escalates when device plugin pod is not crash looping",
+			pod:           newDevicePluginPod("plugin-01", "node-01", false),
+			wantNextCalls: 1,
+		},
+		{
+			name:          "Escalates when device plugin was restarted within the period",
+			pod:           newDevicePluginPod("plugin-01", "node-01", true),
+			restartedAt:   time.Now().Add(-time.Hour),
+			wantNextCalls: 1,
+		},
+		{
+			name:            "Deletes crash looping device plugin pod again after the period",
+			pod:             newDevicePluginPod("plugin-01", "node-01", true),
+			restartedAt:     time.Now().Add(-48 * time.Hour),
+			wantPodDeleted:  true,
+			wantLightweight: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.pod)
+			selector, err := labels.Parse(defaultDevicePluginSelector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := &countingRemediator{}
+			r := NewDevicePluginRemediator(client, "kube-system", selector, 24*time.Hour, next)
+			if !test.restartedAt.IsZero() {
+				r.(*devicePluginRemediator).restarts["node-01"] = test.restartedAt
+			}
+
+			result, err := r.Remediate(t.Context(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-01"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.calls != test.wantNextCalls {
+				t.Errorf("next remediator calls = %d, want %d", next.calls, test.wantNextCalls)
+			}
+			if result.Lightweight != test.wantLightweight {
+				t.Errorf("lightweight = %v, want %v", result.Lightweight, test.wantLightweight)
+			}
+
+			pods, err := client.CoreV1().Pods("kube-system").List(t.Context(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deleted := len(pods.Items) == 0; deleted != test.wantPodDeleted {
+				t.Errorf("pod deleted = %v, want %v", deleted, test.wantPodDeleted)
+			}
+		})
+	}
+}
+
+func TestRemediateNodeDoesNotCountLightweightRemediations(t *testing.T) {
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(newDevicePluginPod("plugin-01", "node-01", true))),
+		WithCivoClient(&FakeClient{}),
+		WithRemediator(&countingRemediator{}),
+		WithDevicePluginNamespace("kube-system"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	if _, err := obj.remediateNode(t.Context(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-01"}}, "node is not ready"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(obj.rebootHistoryOf("node-01")); got != 0 {
+		t.Errorf("reboots = %d, want 0", got)
+	}
+	if _, ok := obj.lastRebootCmdTimes.Load("node-01"); !ok {
+		t.Error("last remediation time was not recorded")
+	}
+}
+
+func TestRunListsDevicePluginPodsOnce(t *testing.T) {
+	var objects []runtime.Object
+	for _, name := range []string{"node-01", "node-02"} {
+		objects = append(objects,
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						nodePoolLabelKey: testNodePoolID,
+					},
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:   corev1.NodeReady,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			newDevicePluginPod("plugin-"+name, name, false),
+		)
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(client),
+		WithCivoClient(newFakeClient()),
+		WithDevicePluginNamespace("kube-system"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	if err := obj.run(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	lists := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "pods" {
+			lists++
+		}
+	}
+	if lists != 1 {
+		t.Errorf("pod lists = %d, want 1", lists)
+	}
+	for _, name := range []string{"node-01", "node-02"} {
+		if state, _ := obj.nodeStateOf(name); state != nodeStateHealthy {
+			t.Errorf("state of %s = %v, want %v", name, state, nodeStateHealthy)
+		}
+	}
+}
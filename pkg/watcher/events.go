@@ -0,0 +1,95 @@
+package watcher
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// eventFlushTimeout is how long pending events are waited for before node-agent stops recording events.
+const eventFlushTimeout = 10 * time.Second
+
+// eventSink writes the events recorded on nodes to Kubernetes, like record.EventBroadcaster.StartRecordingToSink,
+// but marks every event as done once it is written, so that pending events can be waited for.
+type eventSink struct {
+	sink       record.EventSink
+	correlator *record.EventCorrelator
+	pending    *sync.WaitGroup
+}
+
+func (s *eventSink) record(event *corev1.Event) {
+	defer s.pending.Done()
+
+	// The event is shared with the other watchers of the broadcaster.
+	eventCopy := *event
+	result, err := s.correlator.EventCorrelate(&eventCopy)
+	if err != nil {
+		slog.Warn("Failed to correlate event", "reason", event.Reason, "error", err)
+	}
+	if result == nil || result.Skip {
+		return
+	}
+
+	var written *corev1.Event
+	updated := result.Event.Count > 1
+	if updated {
+		written, err = s.sink.Patch(result.Event, result.Patch)
+	}
+	if !updated || apierrors.IsNotFound(err) {
+		result.Event.ResourceVersion = ""
+		written, err = s.sink.Create(result.Event)
+	}
+	if err != nil {
+		slog.Warn("Failed to record event", "object", event.InvolvedObject.Name, "reason", event.Reason, "error", err)
+		return
+	}
+	s.correlator.UpdateState(written)
+}
+
+// pendingEventRecorder is a record.EventRecorder that counts the events it records as pending until they are written.
+type pendingEventRecorder struct {
+	record.EventRecorder
+	pending *sync.WaitGroup
+}
+
+func (r *pendingEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.pending.Add(1)
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+func (r *pendingEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...any) {
+	r.pending.Add(1)
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r *pendingEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...any) {
+	r.pending.Add(1)
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// stopRecordingEvents waits up to eventFlushTimeout for the pending events to be written,
+// and shuts down the event broadcaster once. It does nothing when the event recorder was set with an Option.
+func (w *watcher) stopRecordingEvents() {
+	if w.eventBroadcaster == nil {
+		return
+	}
+
+	w.stopEventsOnce.Do(func() {
+		flushed := make(chan struct{})
+		go func() {
+			w.pendingEvents.Wait()
+			close(flushed)
+		}()
+		select {
+		case <-flushed:
+		case <-time.After(eventFlushTimeout):
+			slog.Warn("Timed out waiting for events to be recorded")
+		}
+		w.eventBroadcaster.Shutdown()
+	})
+}
@@ -0,0 +1,89 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Explanation is why node-agent considers a node healthy or unhealthy, the values it decided on,
+// and what it would do with the node.
+type Explanation struct {
+	NodeReport
+
+	CreatedAt  time.Time              `json:"createdAt"`
+	Conditions []ConditionExplanation `json:"conditions"`
+	// AllocatableGPUs and CapacityGPUs are empty when the node does not report GPUs.
+	AllocatableGPUs string `json:"allocatableGPUs,omitempty"`
+	CapacityGPUs    string `json:"capacityGPUs,omitempty"`
+	DesiredGPUs     int    `json:"desiredGPUs"`
+	// ThresholdTime is the start of the reboot time window. The node is only remediated when its
+	// Ready condition and its last remediation are both before it.
+	RebootTimeWindow   string     `json:"rebootTimeWindow"`
+	ThresholdTime      time.Time  `json:"thresholdTime"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+	LastRemediation    *time.Time `json:"lastRemediation,omitempty"`
+	// PauseReason is why remediation is paused, or empty when it is not.
+	PauseReason string    `json:"pauseReason,omitempty"`
+	EvaluatedAt time.Time `json:"evaluatedAt"`
+}
+
+// ConditionExplanation is a condition of the node.
+type ConditionExplanation struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// Explain evaluates the node once and explains what would be done with it, without remediating it.
+// It returns an error when the node is not in the node pool.
+func (w *watcher) Explain(ctx context.Context, nodeName string) (*Explanation, error) {
+	node, err := w.poolNode(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	pauseReason, err := w.clusterPauseReason(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	e := &Explanation{
+		NodeReport:       w.nodeReport(ctx, node, now, pauseReason),
+		CreatedAt:        node.GetCreationTimestamp().Time,
+		Conditions:       make([]ConditionExplanation, 0, len(node.Status.Conditions)),
+		DesiredGPUs:      w.nodeDesiredGPUCount,
+		RebootTimeWindow: (w.rebootTimeWindowMinutes * time.Minute).String(),
+		ThresholdTime:    now.Add(-w.rebootTimeWindowMinutes * time.Minute),
+		PauseReason:      pauseReason,
+		EvaluatedAt:      now,
+	}
+	for _, cond := range node.Status.Conditions {
+		e.Conditions = append(e.Conditions, ConditionExplanation{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time,
+		})
+		if cond.Type == corev1.NodeReady && !cond.LastTransitionTime.IsZero() {
+			lastTransitionTime := cond.LastTransitionTime.Time
+			e.LastTransitionTime = &lastTransitionTime
+		}
+	}
+	if quantity, ok := node.Status.Allocatable[gpuResourceName]; ok {
+		e.AllocatableGPUs = quantity.String()
+	}
+	if quantity, ok := node.Status.Capacity[gpuResourceName]; ok {
+		e.CapacityGPUs = quantity.String()
+	}
+	// nodeReport has restored the last remediation from the node.
+	if v, ok := w.lastRebootCmdTimes.Load(nodeName); ok {
+		lastRemediation := v.(time.Time)
+		e.LastRemediation = &lastRemediation
+	}
+	return e, nil
+}
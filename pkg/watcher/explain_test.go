@@ -0,0 +1,111 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExplain(t *testing.T) {
+	now := time.Now()
+	transitionedAt := now.Add(-time.Hour).Truncate(time.Second)
+	remediatedAt := now.Add(-time.Minute).Truncate(time.Second)
+
+	newNode := func(annotations map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "node-01",
+				CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+				Labels: map[string]string{
+					nodePoolLabelKey: testNodePoolID,
+				},
+				Annotations: annotations,
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             corev1.ConditionFalse,
+						LastTransitionTime: metav1.NewTime(transitionedAt),
+					},
+				},
+				Allocatable: corev1.ResourceList{
+					gpuResourceName: resource.MustParse("7"),
+				},
+				Capacity: corev1.ResourceList{
+					gpuResourceName: resource.MustParse("8"),
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name                string
+		node                *corev1.Node
+		wantAction          decisionAction
+		wantRule            string
+		wantLastRemediation bool
+	}
+
+	tests := []test{
+		{
+			name:       "Explains that unhealthy node would be remediated",
+			node:       newNode(nil),
+			wantAction: decisionRemediate,
+			wantRule:   ruleUnhealthy,
+		},
+		{
+			name: "Explains that unhealthy node would be skipped because it was remediated recently",
+			node: newNode(map[string]string{
+				lastRemediationKey: remediatedAt.UTC().Format(time.RFC3339),
+			}),
+			wantAction:          decisionSkip,
+			wantRule:            ruleCooldown,
+			wantLastRemediation: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset(test.node)),
+				WithCivoClient(newFakeClient()),
+				WithDesiredGPUCount(testNodeDesiredGPUCount),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			e, err := w.Explain(t.Context(), "node-01")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Action != string(test.wantAction) || e.Rule != test.wantRule {
+				t.Errorf("decision = %s by %s, want %s by %s (%s)", e.Action, e.Rule, test.wantAction, test.wantRule, e.Reason)
+			}
+			if e.Healthy {
+				t.Errorf("healthy = true, want false")
+			}
+			if e.AllocatableGPUs != "7" || e.CapacityGPUs != "8" || e.DesiredGPUs != 8 {
+				t.Errorf("GPUs = %s/%s desired %d, want 7/8 desired 8", e.AllocatableGPUs, e.CapacityGPUs, e.DesiredGPUs)
+			}
+			if e.LastTransitionTime == nil || !e.LastTransitionTime.Equal(transitionedAt) {
+				t.Errorf("last transition time = %v, want %v", e.LastTransitionTime, transitionedAt)
+			}
+			if len(e.Conditions) != 1 {
+				t.Errorf("conditions = %d, want 1", len(e.Conditions))
+			}
+			if got := e.LastRemediation != nil; got != test.wantLastRemediation {
+				t.Errorf("last remediation = %v, want set %v", e.LastRemediation, test.wantLastRemediation)
+			}
+			if want := now.Add(-testRebootTimeWindowMinutes * time.Minute); e.ThresholdTime.Sub(want).Abs() > time.Minute {
+				t.Errorf("threshold time = %v, want about %v", e.ThresholdTime, want)
+			}
+		})
+	}
+}
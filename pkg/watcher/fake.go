@@ -6,7 +6,11 @@ import "github.com/civo/civogo"
 // when FakeClient alone is not sufficient.
 type FakeClient struct {
 	HardRebootInstanceFunc            func(id string) (*civogo.SimpleResponse, error)
+	SoftRebootInstanceFunc            func(id string) (*civogo.SimpleResponse, error)
+	StartInstanceFunc                 func(id string) (*civogo.SimpleResponse, error)
+	RecycleKubernetesClusterFunc      func(id, hostname string) (*civogo.SimpleResponse, error)
 	FindKubernetesClusterInstanceFunc func(clusterID, search string) (*civogo.Instance, error)
+	GetKubernetesClusterFunc          func(id string) (*civogo.KubernetesCluster, error)
 
 	*civogo.FakeClient
 }
@@ -18,6 +22,20 @@ func (f *FakeClient) HardRebootInstance(id string) (*civogo.SimpleResponse, erro
 	return f.FakeClient.HardRebootInstance(id)
 }
 
+func (f *FakeClient) SoftRebootInstance(id string) (*civogo.SimpleResponse, error) {
+	if f.SoftRebootInstanceFunc != nil {
+		return f.SoftRebootInstanceFunc(id)
+	}
+	return f.FakeClient.SoftRebootInstance(id)
+}
+
+func (f *FakeClient) StartInstance(id string) (*civogo.SimpleResponse, error) {
+	if f.StartInstanceFunc != nil {
+		return f.StartInstanceFunc(id)
+	}
+	return f.FakeClient.StartInstance(id)
+}
+
 func (f *FakeClient) FindKubernetesClusterInstance(clusterID, search string) (*civogo.Instance, error) {
 	if f.FindKubernetesClusterInstanceFunc != nil {
 		return f.FindKubernetesClusterInstanceFunc(clusterID, search)
@@ -25,4 +43,18 @@ func (f *FakeClient) FindKubernetesClusterInstance(clusterID, search string) (*c
 	return f.FakeClient.FindKubernetesClusterInstance(clusterID, search)
 }
 
+func (f *FakeClient) GetKubernetesCluster(id string) (*civogo.KubernetesCluster, error) {
+	if f.GetKubernetesClusterFunc != nil {
+		return f.GetKubernetesClusterFunc(id)
+	}
+	return f.FakeClient.GetKubernetesCluster(id)
+}
+
+func (f *FakeClient) RecycleKubernetesCluster(id, hostname string) (*civogo.SimpleResponse, error) {
+	if f.RecycleKubernetesClusterFunc != nil {
+		return f.RecycleKubernetesClusterFunc(id, hostname)
+	}
+	return f.FakeClient.RecycleKubernetesCluster(id, hostname)
+}
+
 var _ civogo.Clienter = (*FakeClient)(nil)
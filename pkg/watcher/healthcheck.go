@@ -0,0 +1,212 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Severity is how serious an unhealthy Verdict is.
+type Severity int
+
+const (
+	// SeverityInfo is used for healthy verdicts.
+	SeverityInfo Severity = iota
+	// SeverityWarning marks a node as unhealthy in reports, but does not trigger remediation.
+	SeverityWarning
+	// SeverityCritical marks a node as unhealthy and triggers remediation.
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Verdict is the result of a HealthCheck for a node.
+type Verdict struct {
+	Healthy  bool
+	Reason   string
+	Severity Severity
+}
+
+// Healthy returns a healthy Verdict with the reason.
+func Healthy(reason string) Verdict {
+	return Verdict{
+		Healthy:  true,
+		Reason:   reason,
+		Severity: SeverityInfo,
+	}
+}
+
+// Unhealthy returns an unhealthy Verdict with the reason and severity.
+func Unhealthy(severity Severity, reason string) Verdict {
+	return Verdict{
+		Healthy:  false,
+		Reason:   reason,
+		Severity: severity,
+	}
+}
+
+// HealthCheck evaluates the health of a node.
+type HealthCheck interface {
+	// Name returns the name of the check, which is used in logs and reports.
+	Name() string
+	// Check returns the Verdict for the node.
+	Check(ctx context.Context, node *corev1.Node) Verdict
+}
+
+// prefetcher is implemented by health checks that look up what they need for all nodes at once,
+// instead of once for every node they check.
+type prefetcher interface {
+	// prefetch looks up what the check needs, and returns a context the check finds it in.
+	prefetch(ctx context.Context) (context.Context, error)
+}
+
+// prefetchChecks returns a context with what the health checks need for all nodes. When a check fails to
+// look it up, it is left out, so that the check looks it up for each node instead.
+func (w *watcher) prefetchChecks(ctx context.Context) context.Context {
+	for _, check := range w.healthChecks {
+		p, ok := check.(prefetcher)
+		if !ok {
+			continue
+		}
+		prefetched, err := p.prefetch(ctx)
+		if err != nil {
+			slog.Warn("Failed to prefetch health check", "check", check.Name(), "error", err)
+			continue
+		}
+		ctx = prefetched
+	}
+	return ctx
+}
+
+// checkResult is the Verdict of a named HealthCheck.
+type checkResult struct {
+	name    string
+	verdict Verdict
+}
+
+// evaluation is the result of all health checks for a node.
+type evaluation struct {
+	results []checkResult
+}
+
+// needsRemediation checks if any health check found the node critically unhealthy.
+func (e evaluation) needsRemediation() bool {
+	for _, r := range e.results {
+		if !r.verdict.Healthy && r.verdict.Severity >= SeverityCritical {
+			return true
+		}
+	}
+	return false
+}
+
+// reason returns the reasons of all unhealthy verdicts, or a generic reason when the node is healthy.
+func (e evaluation) reason() string {
+	var reasons []string
+	for _, r := range e.results {
+		if !r.verdict.Healthy {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", r.name, r.verdict.Reason))
+		}
+	}
+	if len(reasons) == 0 {
+		return "all health checks passed"
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// evaluateNode runs every health check against the node.
+func (w *watcher) evaluateNode(ctx context.Context, node *corev1.Node) evaluation {
+	var e evaluation
+	for _, check := range w.healthChecks {
+		e.results = append(e.results, checkResult{
+			name:    check.Name(),
+			verdict: check.Check(ctx, node),
+		})
+	}
+	return e
+}
+
+type readyCheck struct{}
+
+// NewReadyCheck returns a HealthCheck that finds nodes whose NodeReady condition is not true critically unhealthy.
+func NewReadyCheck() HealthCheck {
+	return readyCheck{}
+}
+
+func (readyCheck) Name() string {
+	return "Ready"
+}
+
+func (readyCheck) Check(_ context.Context, node *corev1.Node) Verdict {
+	if !isNodeReady(node) {
+		return Unhealthy(SeverityCritical, "node is not ready")
+	}
+	return Healthy("node is ready")
+}
+
+type gpuCountCheck struct {
+	desired int
+}
+
+// NewGPUCountCheck returns a HealthCheck that finds nodes whose allocatable GPU count does not match
+// the desired count critically unhealthy. The check always passes when desired is 0.
+func NewGPUCountCheck(desired int) HealthCheck {
+	return gpuCountCheck{
+		desired: desired,
+	}
+}
+
+func (gpuCountCheck) Name() string {
+	return "GPUCount"
+}
+
+func (c gpuCountCheck) Check(_ context.Context, node *corev1.Node) Verdict {
+	if !isNodeDesiredGPU(node, c.desired) {
+		return Unhealthy(SeverityCritical, fmt.Sprintf("allocatable GPU count does not match the desired count of %d", c.desired))
+	}
+	return Healthy("allocatable GPU count matches the desired count")
+}
+
+type resourceCapacityCheck struct {
+	resource corev1.ResourceName
+}
+
+// NewResourceCapacityCheck returns a HealthCheck that finds nodes whose allocatable amount of the extended
+// resource is lower than its capacity critically unhealthy. Device plugins, such as the NVIDIA one, reduce
+// the allocatable amount when a device is marked unhealthy while the capacity stays the same, so this catches
+// failed devices without knowing how many each node should have.
+func NewResourceCapacityCheck(resource string) HealthCheck {
+	return resourceCapacityCheck{
+		resource: corev1.ResourceName(resource),
+	}
+}
+
+func (c resourceCapacityCheck) Name() string {
+	return "ResourceCapacity:" + string(c.resource)
+}
+
+func (c resourceCapacityCheck) Check(_ context.Context, node *corev1.Node) Verdict {
+	capacity, ok := node.Status.Capacity[c.resource]
+	if !ok {
+		return Healthy(fmt.Sprintf("node has no %s capacity", c.resource))
+	}
+	allocatable := node.Status.Allocatable[c.resource]
+	if allocatable.Cmp(capacity) < 0 {
+		return Unhealthy(SeverityCritical, fmt.Sprintf("allocatable %s count %s is lower than the capacity of %s",
+			c.resource, allocatable.String(), capacity.String()))
+	}
+	return Healthy(fmt.Sprintf("allocatable %s count matches the capacity", c.resource))
+}
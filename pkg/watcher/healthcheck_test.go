@@ -0,0 +1,171 @@
+package watcher
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// staticCheck is a HealthCheck that always returns the same Verdict.
+type staticCheck struct {
+	verdict Verdict
+}
+
+func (staticCheck) Name() string {
+	return "Static"
+}
+
+func (c staticCheck) Check(context.Context, *corev1.Node) Verdict {
+	return c.verdict
+}
+
+func TestEvaluateNode(t *testing.T) {
+	healthyNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+			Allocatable: corev1.ResourceList{
+				gpuResourceName: resource.MustParse("8"),
+			},
+		},
+	}
+	notReadyNode := healthyNode.DeepCopy()
+	notReadyNode.Status.Conditions[0].Status = corev1.ConditionFalse
+
+	type test struct {
+		name                 string
+		node                 *corev1.Node
+		checks               []HealthCheck
+		wantResults          int
+		wantNeedsRemediation bool
+	}
+
+	tests := []test{
+		{
+			name:                 "Does not need remediation when built-in checks pass",
+			node:                 healthyNode,
+			wantResults:          2,
+			wantNeedsRemediation: false,
+		},
+		{
+			name:                 "Needs remediation when the built-in Ready check fails",
+			node:                 notReadyNode,
+			wantResults:          2,
+			wantNeedsRemediation: true,
+		},
+		{
+			name: "Needs remediation when an additional check is critically unhealthy",
+			node: healthyNode,
+			checks: []HealthCheck{
+				staticCheck{verdict: Unhealthy(SeverityCritical, "broken")},
+			},
+			wantResults:          3,
+			wantNeedsRemediation: true,
+		},
+		{
+			name: "Does not need remediation when an additional check is only a warning",
+			node: healthyNode,
+			checks: []HealthCheck{
+				staticCheck{verdict: Unhealthy(SeverityWarning, "degraded")},
+			},
+			wantResults:          3,
+			wantNeedsRemediation: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(&FakeClient{}),
+				WithDesiredGPUCount(testNodeDesiredGPUCount),
+				WithHealthChecks(test.checks...),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			e := w.(*watcher).evaluateNode(t.Context(), test.node)
+			if len(e.results) != test.wantResults {
+				t.Errorf("results = %v, want %d results", e.results, test.wantResults)
+			}
+			if got := e.needsRemediation(); got != test.wantNeedsRemediation {
+				t.Errorf("needsRemediation = %v (reason: %q), want %v", got, e.reason(), test.wantNeedsRemediation)
+			}
+		})
+	}
+}
+
+func TestResourceCapacityCheck(t *testing.T) {
+	newNode := func(allocatable, capacity string) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-01",
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{},
+				Capacity:    corev1.ResourceList{},
+			},
+		}
+		if allocatable != "" {
+			node.Status.Allocatable[gpuResourceName] = resource.MustParse(allocatable)
+		}
+		if capacity != "" {
+			node.Status.Capacity[gpuResourceName] = resource.MustParse(capacity)
+		}
+		return node
+	}
+
+	type test struct {
+		name        string
+		node        *corev1.Node
+		wantHealthy bool
+	}
+
+	tests := []test{
+		{
+			name:        "Returns healthy when allocatable matches capacity",
+			node:        newNode("8", "8"),
+			wantHealthy: true,
+		},
+		{
+			name:        "Returns unhealthy when allocatable is lower than capacity",
+			node:        newNode("7", "8"),
+			wantHealthy: false,
+		},
+		{
+			name:        "Returns unhealthy when allocatable is missing",
+			node:        newNode("", "8"),
+			wantHealthy: false,
+		},
+		{
+			name:        "Returns healthy when node has no capacity of the resource",
+			node:        newNode("", ""),
+			wantHealthy: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verdict := NewResourceCapacityCheck(string(gpuResourceName)).Check(t.Context(), test.node)
+			if verdict.Healthy != test.wantHealthy {
+				t.Errorf("healthy = %v, want %v (%s)", verdict.Healthy, test.wantHealthy, verdict.Reason)
+			}
+			if !verdict.Healthy && verdict.Severity != SeverityCritical {
+				t.Errorf("severity = %v, want %v", verdict.Severity, SeverityCritical)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxNextAllowedIterations bounds the search for the next time reboots are allowed,
+// so that a combination of windows and blackouts that never allows a reboot does not loop forever.
+const maxNextAllowedIterations = 1000
+
+// blackoutTimeLayout is the layout of blackout period boundaries without a timezone offset,
+// which are interpreted in the timezone of the maintenance policy.
+const blackoutTimeLayout = "2006-01-02T15:04"
+
+// maintenanceWindow is a recurring window, starting at each activation of the cron schedule
+// and lasting for the duration, during which reboots are allowed.
+type maintenanceWindow struct {
+	spec     string
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// blackoutPeriod is a period during which reboots are never allowed.
+type blackoutPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// maintenancePolicy decides when automated reboots may happen.
+// Reboots are allowed at any time when no windows are configured,
+// but never during a blackout period.
+type maintenancePolicy struct {
+	location  *time.Location
+	windows   []maintenanceWindow
+	blackouts []blackoutPeriod
+}
+
+// newMaintenancePolicy parses the maintenance windows, blackout periods and timezone.
+//
+// windows is a semicolon-separated list of a standard 5-field cron expression followed by a duration,
+// e.g. "0 2 * * 1-5 4h; 0 0 * * 6 24h".
+// blackouts is a comma-separated list of periods in the form of start/end, where start and end are
+// either RFC3339 timestamps or "2006-01-02T15:04" in the given timezone, e.g. "2025-12-24T00:00/2025-12-27T00:00".
+// timezone is an IANA timezone name and defaults to UTC.
+func newMaintenancePolicy(windows, blackouts, timezone string) (*maintenancePolicy, error) {
+	p := &maintenancePolicy{
+		location: time.UTC,
+	}
+
+	if timezone = strings.TrimSpace(timezone); timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reboot window timezone %q: %w", timezone, err)
+		}
+		p.location = loc
+	}
+
+	for _, spec := range strings.Split(windows, ";") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		window, err := parseMaintenanceWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		p.windows = append(p.windows, window)
+	}
+
+	for _, spec := range strings.Split(blackouts, ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		blackout, err := parseBlackoutPeriod(spec, p.location)
+		if err != nil {
+			return nil, err
+		}
+		p.blackouts = append(p.blackouts, blackout)
+	}
+	return p, nil
+}
+
+func parseMaintenanceWindow(spec string) (maintenanceWindow, error) {
+	i := strings.LastIndex(spec, " ")
+	if i < 0 {
+		return maintenanceWindow{}, fmt.Errorf("invalid reboot window %q: expected a cron expression followed by a duration", spec)
+	}
+
+	duration, err := time.ParseDuration(spec[i+1:])
+	if err != nil || duration <= 0 {
+		return maintenanceWindow{}, fmt.Errorf("invalid reboot window %q: invalid duration %q", spec, spec[i+1:])
+	}
+
+	schedule, err := cron.ParseStandard(strings.TrimSpace(spec[:i]))
+	if err != nil {
+		return maintenanceWindow{}, fmt.Errorf("invalid reboot window %q: %w", spec, err)
+	}
+	return maintenanceWindow{
+		spec:     spec,
+		schedule: schedule,
+		duration: duration,
+	}, nil
+}
+
+func parseBlackoutPeriod(spec string, loc *time.Location) (blackoutPeriod, error) {
+	startStr, endStr, ok := strings.Cut(spec, "/")
+	if !ok {
+		return blackoutPeriod{}, fmt.Errorf("invalid reboot blackout %q: expected start/end", spec)
+	}
+
+	start, err := parseBlackoutTime(strings.TrimSpace(startStr), loc)
+	if err != nil {
+		return blackoutPeriod{}, fmt.Errorf("invalid reboot blackout %q: %w", spec, err)
+	}
+	end, err := parseBlackoutTime(strings.TrimSpace(endStr), loc)
+	if err != nil {
+		return blackoutPeriod{}, fmt.Errorf("invalid reboot blackout %q: %w", spec, err)
+	}
+	if !end.After(start) {
+		return blackoutPeriod{}, fmt.Errorf("invalid reboot blackout %q: end must be after start", spec)
+	}
+	return blackoutPeriod{
+		start: start,
+		end:   end,
+	}, nil
+}
+
+func parseBlackoutTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(blackoutTimeLayout, s, loc)
+}
+
+// allows checks if reboots are allowed at t, and returns the reason when they are not.
+func (p *maintenancePolicy) allows(t time.Time) (bool, string) {
+	if b, ok := p.blackoutAt(t); ok {
+		return false, fmt.Sprintf("blackout period from %s to %s", b.start.In(p.location).Format(time.RFC3339), b.end.In(p.location).Format(time.RFC3339))
+	}
+	if len(p.windows) == 0 {
+		return true, ""
+	}
+	for _, window := range p.windows {
+		if window.activeAt(t.In(p.location)) {
+			return true, ""
+		}
+	}
+	return false, "outside of maintenance windows"
+}
+
+// nextAllowed returns the earliest time at or after t when reboots are allowed.
+// It returns the zero time when no such time can be found.
+func (p *maintenancePolicy) nextAllowed(t time.Time) time.Time {
+	candidate := t.In(p.location)
+	for range maxNextAllowedIterations {
+		if ok, _ := p.allows(candidate); ok {
+			return candidate
+		}
+		if b, ok := p.blackoutAt(candidate); ok {
+			candidate = b.end.In(p.location)
+			continue
+		}
+
+		var next time.Time
+		for _, window := range p.windows {
+			if n := window.schedule.Next(candidate); next.IsZero() || n.Before(next) {
+				next = n
+			}
+		}
+		if next.IsZero() {
+			return time.Time{}
+		}
+		candidate = next
+	}
+	return time.Time{}
+}
+
+func (p *maintenancePolicy) blackoutAt(t time.Time) (blackoutPeriod, bool) {
+	for _, b := range p.blackouts {
+		if !t.Before(b.start) && t.Before(b.end) {
+			return b, true
+		}
+	}
+	return blackoutPeriod{}, false
+}
+
+// activeAt checks if the window started within its duration before t.
+func (w maintenanceWindow) activeAt(t time.Time) bool {
+	return !w.schedule.Next(t.Add(-w.duration)).After(t)
+}
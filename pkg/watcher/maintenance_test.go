@@ -0,0 +1,204 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewMaintenancePolicy(t *testing.T) {
+	type args struct {
+		windows   string
+		blackouts string
+		timezone  string
+	}
+	type test struct {
+		name        string
+		args        args
+		wantWindows int
+		wantErr     bool
+	}
+
+	tests := []test{
+		{
+			name:        "Returns no error when nothing is configured",
+			args:        args{},
+			wantWindows: 0,
+		},
+		{
+			name: "Returns no error when given valid windows, blackouts and timezone",
+			args: args{
+				windows:   "0 2 * * 1-5 4h; 0 0 * * 6 48h",
+				blackouts: "2025-12-24T00:00/2025-12-27T00:00, 2026-01-01T00:00:00Z/2026-01-02T00:00:00Z",
+				timezone:  "Europe/London",
+			},
+			wantWindows: 2,
+		},
+		{
+			name: "Returns an error when window has no duration",
+			args: args{
+				windows: "0 2 * * 1-5",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns an error when window has an invalid cron expression",
+			args: args{
+				windows: "0 25 * * * 4h",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns an error when blackout end is before start",
+			args: args{
+				blackouts: "2025-12-27T00:00/2025-12-24T00:00",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns an error when blackout has no end",
+			args: args{
+				blackouts: "2025-12-24T00:00",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns an error when timezone is unknown",
+			args: args{
+				timezone: "Mars/Olympus_Mons",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := newMaintenancePolicy(test.args.windows, test.args.blackouts, test.args.timezone)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && len(p.windows) != test.wantWindows {
+				t.Errorf("windows = %d, want %d", len(p.windows), test.wantWindows)
+			}
+		})
+	}
+}
+
+func TestMaintenancePolicyAllows(t *testing.T) {
+	type test struct {
+		name      string
+		windows   string
+		blackouts string
+		timezone  string
+		now       time.Time
+		want      bool
+	}
+
+	tests := []test{
+		{
+			name: "Returns true when no windows or blackouts are configured",
+			now:  time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name:    "Returns true when inside a window",
+			windows: "0 2 * * * 4h",
+			now:     time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC),
+			want:    true,
+		},
+		{
+			name:    "Returns true at the start of a window",
+			windows: "0 2 * * * 4h",
+			now:     time.Date(2025, 6, 2, 2, 0, 0, 0, time.UTC),
+			want:    true,
+		},
+		{
+			name:    "Returns false when outside of all windows",
+			windows: "0 2 * * * 4h",
+			now:     time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+			want:    false,
+		},
+		{
+			name:     "Returns true when inside a window in the configured timezone",
+			windows:  "0 2 * * * 1h",
+			timezone: "Asia/Tokyo",
+			now:      time.Date(2025, 6, 1, 17, 30, 0, 0, time.UTC), // 02:30 in Tokyo
+			want:     true,
+		},
+		{
+			name:      "Returns false when inside a window but also inside a blackout",
+			windows:   "0 2 * * * 4h",
+			blackouts: "2025-06-01T00:00/2025-06-03T00:00",
+			now:       time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC),
+			want:      false,
+		},
+		{
+			name:      "Returns true when blackout has ended",
+			blackouts: "2025-06-01T00:00/2025-06-02T00:00",
+			now:       time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+			want:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := newMaintenancePolicy(test.windows, test.blackouts, test.timezone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, reason := p.allows(test.now)
+			if got != test.want {
+				t.Errorf("got = %v (reason: %q), want %v", got, reason, test.want)
+			}
+		})
+	}
+}
+
+func TestMaintenancePolicyNextAllowed(t *testing.T) {
+	type test struct {
+		name      string
+		windows   string
+		blackouts string
+		now       time.Time
+		want      time.Time
+	}
+
+	tests := []test{
+		{
+			name: "Returns now when reboots are allowed",
+			now:  time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Returns the start of the next window when outside of all windows",
+			windows: "0 2 * * * 4h",
+			now:     time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+			want:    time.Date(2025, 6, 3, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Returns the end of the blackout when no windows are configured",
+			blackouts: "2025-06-01T00:00/2025-06-03T00:00",
+			now:       time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+			want:      time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Returns the start of the first window after the blackout",
+			windows:   "0 2 * * * 4h",
+			blackouts: "2025-06-01T00:00/2025-06-03T12:00",
+			now:       time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+			want:      time.Date(2025, 6, 4, 2, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := newMaintenancePolicy(test.windows, test.blackouts, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := p.nextAllowed(test.now)
+			if !got.Equal(test.want) {
+				t.Errorf("got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
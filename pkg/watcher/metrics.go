@@ -0,0 +1,39 @@
+package watcher
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const metricsNamespace = "node_agent"
+
+var remediationPaused = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: metricsNamespace,
+	Name:      "remediation_paused",
+	Help:      "Whether remediation of the node pool is paused because the cluster is not in a steady state (1) or not (0).",
+}, []string{"cluster_id", "node_pool_id"})
+
+var nodesQuarantinedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Name:      "nodes_quarantined_total",
+	Help:      "Total number of nodes quarantined because they were rebooted too many times.",
+}, []string{"cluster_id", "node_pool_id"})
+
+var remediationOutcomesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Name:      "remediation_outcomes_total",
+	Help:      "Total number of remediations by outcome (recovered, still_unhealthy, instance_gone).",
+}, []string{"cluster_id", "node_pool_id", "outcome"})
+
+var remediationRecoverySeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metricsNamespace,
+	Name:      "remediation_recovery_seconds",
+	Help:      "Time it took for a node to recover after it was remediated.",
+	Buckets:   []float64{60, 120, 300, 600, 900, 1200, 1800, 2400, 3600},
+}, []string{"cluster_id", "node_pool_id"})
+
+var nodesByState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: metricsNamespace,
+	Name:      "nodes",
+	Help:      "Number of watched nodes by remediation lifecycle state.",
+}, []string{"cluster_id", "node_pool_id", "state"})
@@ -0,0 +1,190 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NotificationEvent is the type of a Notification.
+type NotificationEvent string
+
+const (
+	// NotificationUnhealthy is sent when a node is found unhealthy.
+	NotificationUnhealthy NotificationEvent = "unhealthy"
+	// NotificationRemediated is sent when a node is remediated, e.g. rebooted.
+	NotificationRemediated NotificationEvent = "remediated"
+	// NotificationSkipped is sent when the remediation of an unhealthy node is queued until the next maintenance window,
+	// or held back because the node was remediated, or its Ready condition changed, within the reboot time window.
+	// It is sent once, when the node starts being skipped for that reason.
+	NotificationSkipped NotificationEvent = "skipped"
+	// NotificationFailed is sent when a node could not be remediated, or did not recover after its remediation.
+	NotificationFailed NotificationEvent = "failed"
+)
+
+// notificationEvents are all types of notifications.
+var notificationEvents = []NotificationEvent{
+	NotificationUnhealthy,
+	NotificationRemediated,
+	NotificationSkipped,
+	NotificationFailed,
+}
+
+// Notification is something node-agent did or decided about a node.
+type Notification struct {
+	Event      NotificationEvent `json:"event"`
+	ClusterID  string            `json:"clusterID"`
+	NodePoolID string            `json:"nodePoolID"`
+	Node       string            `json:"node"`
+	InstanceID string            `json:"instanceID,omitempty"`
+	Action     string            `json:"action,omitempty"`
+	Reason     string            `json:"reason"`
+	// Attempt is the number of remediations of the node within the max reboots period, including this one.
+	Attempt int       `json:"attempt"`
+	Time    time.Time `json:"time"`
+}
+
+// Notifier sends notifications about nodes to people or other systems.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Defaults of the webhook notifier.
+const (
+	defaultWebhookTimeout      = 10 * time.Second
+	defaultWebhookRetryBackoff = time.Second
+)
+
+type webhookNotifier struct {
+	url          string
+	client       *http.Client
+	tmpl         *template.Template
+	events       []NotificationEvent
+	retries      int
+	retryBackoff time.Duration
+}
+
+// NewWebhookNotifier returns a Notifier that POSTs notifications to the URL.
+//
+// The payload is the JSON encoded Notification, unless a text/template is given, which is executed with
+// the Notification to render the payload, e.g. `{"text": {{ printf "%s is %s: %s" .Node .Event .Reason | json }}}`.
+// The json function encodes a value as JSON. Only notifications of the given events are sent, or all of them
+// when no events are given. Failed requests are retried up to the given number of times.
+func NewWebhookNotifier(url, tmpl string, events []NotificationEvent, retries int) (Notifier, error) {
+	n := &webhookNotifier{
+		url:          url,
+		client:       &http.Client{Timeout: defaultWebhookTimeout},
+		events:       events,
+		retries:      retries,
+		retryBackoff: defaultWebhookRetryBackoff,
+	}
+	for _, event := range events {
+		if !slices.Contains(notificationEvents, event) {
+			return nil, fmt.Errorf("unknown webhook event %q", event)
+		}
+	}
+	if tmpl != "" {
+		t, err := template.New("webhook").Funcs(template.FuncMap{
+			"json": func(v any) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		n.tmpl = t
+	}
+	return n, nil
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	if len(n.events) > 0 && !slices.Contains(n.events, notification.Event) {
+		return nil
+	}
+
+	payload, err := n.payload(notification)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = n.post(ctx, payload)
+		if err == nil || attempt >= n.retries {
+			return err
+		}
+		slog.Info("Retrying webhook notification", "event", notification.Event, "node", notification.Node, "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(n.retryBackoff * time.Duration(1<<attempt)):
+		}
+	}
+}
+
+func (n *webhookNotifier) payload(notification Notification) ([]byte, error) {
+	if n.tmpl == nil {
+		return json.Marshal(notification)
+	}
+	var buf bytes.Buffer
+	if err := n.tmpl.Execute(&buf, notification); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *webhookNotifier) post(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// parseNotificationEvents parses comma-separated notification events.
+func parseNotificationEvents(s string) []NotificationEvent {
+	var events []NotificationEvent
+	for _, event := range strings.Split(s, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, NotificationEvent(event))
+		}
+	}
+	return events
+}
+
+// notify sends the notification in the background, so that a slow receiver does not hold up reconciling.
+func (w *watcher) notify(ctx context.Context, n Notification) {
+	if w.notifier == nil {
+		return
+	}
+
+	n.ClusterID = w.clusterID
+	n.NodePoolID = w.nodePoolID
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	w.notifications.Add(1)
+	go func() {
+		defer w.notifications.Done()
+		if err := w.notifier.Notify(context.WithoutCancel(ctx), n); err != nil {
+			slog.Error("Failed to send notification", "event", n.Event, "node", n.Node, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,230 @@
+package watcher
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// webhookReceiver is a local webhook endpoint that records the payloads it receives.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	payloads []string
+	// failures is the number of requests that are answered with an error before succeeding.
+	failures int
+}
+
+func (r *webhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failures > 0 {
+		r.failures--
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	r.payloads = append(r.payloads, string(body))
+}
+
+func (r *webhookReceiver) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.payloads...)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	notification := Notification{
+		Event:      NotificationRemediated,
+		ClusterID:  testClusterID,
+		NodePoolID: testNodePoolID,
+		Node:       "node-01",
+		InstanceID: "instance-01",
+		Action:     "hard reboot",
+		Reason:     "Ready: node is not ready",
+		Attempt:    2,
+		Time:       time.Date(2025, 1, 1, 2, 14, 0, 0, time.UTC),
+	}
+
+	type test struct {
+		name         string
+		tmpl         string
+		events       []NotificationEvent
+		retries      int
+		failures     int
+		wantErr      bool
+		wantPayloads []string
+	}
+
+	tests := []test{
+		{
+			name:         "Sends the JSON encoded notification by default",
+			wantPayloads: []string{`{"event":"remediated","clusterID":"` + testClusterID + `","nodePoolID":"` + testNodePoolID + `","node":"node-01","instanceID":"instance-01","action":"hard reboot","reason":"Ready: node is not ready","attempt":2,"time":"2025-01-01T02:14:00Z"}`},
+		},
+		{
+			name:         "Sends the payload rendered with the template",
+			tmpl:         `{"text": {{ printf "%s %s (%d): %s" .Node .Event .Attempt .Reason | json }}}`,
+			wantPayloads: []string{`{"text": "node-01 remediated (2): Ready: node is not ready"}`},
+		},
+		{
+			name:   "Does not send notifications of filtered out events",
+			events: []NotificationEvent{NotificationFailed},
+		},
+		{
+			name:         "Retries failed requests",
+			retries:      2,
+			failures:     2,
+			wantPayloads: []string{`{"event":"remediated","clusterID":"` + testClusterID + `","nodePoolID":"` + testNodePoolID + `","node":"node-01","instanceID":"instance-01","action":"hard reboot","reason":"Ready: node is not ready","attempt":2,"time":"2025-01-01T02:14:00Z"}`},
+		},
+		{
+			name:     "Returns error when retries are exhausted",
+			retries:  1,
+			failures: 2,
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			receiver := &webhookReceiver{failures: test.failures}
+			srv := httptest.NewServer(receiver)
+			defer srv.Close()
+
+			n, err := NewWebhookNotifier(srv.URL, test.tmpl, test.events, test.retries)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n.(*webhookNotifier).retryBackoff = time.Millisecond
+
+			err = n.Notify(t.Context(), notification)
+			if (err != nil) != test.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, test.wantErr)
+			}
+
+			got := receiver.received()
+			if len(got) != len(test.wantPayloads) {
+				t.Fatalf("payloads = %v, want %v", got, test.wantPayloads)
+			}
+			for i := range got {
+				if got[i] != test.wantPayloads[i] {
+					t.Errorf("payloads[%d] = %s, want %s", i, got[i], test.wantPayloads[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewWebhookNotifier(t *testing.T) {
+	if _, err := NewWebhookNotifier("http://localhost", "{{ .Node", nil, 0); err == nil {
+		t.Error("expected error for invalid template")
+	}
+	if _, err := NewWebhookNotifier("http://localhost", "", []NotificationEvent{"rebooted"}, 0); err == nil {
+		t.Error("expected error for unknown event")
+	}
+}
+
+func TestRemediateNodeNotifies(t *testing.T) {
+	receiver := &webhookReceiver{}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(&FakeClient{}),
+		WithRemediator(&countingRemediator{}),
+		WithWebhookURL(srv.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-01"},
+		Spec:       corev1.NodeSpec{ProviderID: "civo://instance-01"},
+	}
+	if _, err := obj.remediateNode(t.Context(), node, "Ready: node is not ready"); err != nil {
+		t.Fatal(err)
+	}
+	obj.notifications.Wait()
+
+	got := receiver.received()
+	if len(got) != 1 {
+		t.Fatalf("payloads = %v, want 1 payload", got)
+	}
+	var n Notification
+	if err := json.Unmarshal([]byte(got[0]), &n); err != nil {
+		t.Fatal(err)
+	}
+	if n.Event != NotificationRemediated || n.Node != "node-01" || n.Action != "count" || n.Reason != "Ready: node is not ready" || n.Attempt != 1 {
+		t.Errorf("notification = %+v, want remediated notification of node-01", n)
+	}
+}
+
+func TestRunNotifiesSkippedOnce(t *testing.T) {
+	receiver := &webhookReceiver{}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.Now(),
+				},
+			},
+		},
+	}
+	remediator := &countingRemediator{}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(newFakeClient()),
+		WithRemediator(remediator),
+		WithWebhookURL(srv.URL),
+		WithWebhookEvents(string(NotificationSkipped)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	for range 2 {
+		if err := obj.run(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	obj.notifications.Wait()
+
+	if remediator.calls != 0 {
+		t.Errorf("remediations = %d, want 0", remediator.calls)
+	}
+	got := receiver.received()
+	if len(got) != 1 {
+		t.Fatalf("payloads = %v, want 1 payload", got)
+	}
+	var n Notification
+	if err := json.Unmarshal([]byte(got[0]), &n); err != nil {
+		t.Fatal(err)
+	}
+	if n.Event != NotificationSkipped || n.Node != "node-01" {
+		t.Errorf("notification = %+v, want skipped notification of node-01", n)
+	}
+}
@@ -3,10 +3,12 @@ package watcher
 import (
 	"log/slog"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/civo/civogo"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 )
 
 // Option represents a configuration function that modifies watcher object.
@@ -15,6 +17,23 @@ type Option func(*watcher)
 var defaultOptions = []Option{
 	WithRebootTimeWindowMinutes("40"),
 	WithDesiredGPUCount("0"),
+	WithNodeStartupGracePeriodMinutes("0"),
+	WithOptIn("false"),
+	WithSkipCordonedNodes("true"),
+	WithRebootWindowOverrideMinutes("0"),
+	WithMaxRebootsPerNode("0"),
+	WithMaxRebootsPeriodMinutes("1440"),
+	WithVerificationTimeoutMinutes("0"),
+	WithRemediation(RemediationHardReboot),
+	WithDevicePluginSelector(defaultDevicePluginSelector),
+	WithDevicePluginRestartPeriodMinutes("1440"),
+	WithStuckPodCleanup("false"),
+	WithWebhookRetries("3"),
+	WithApprovalTimeoutSeconds("30"),
+	WithApprovalDefault("deny"),
+	WithReconcileIntervalSeconds("10"),
+	WithReconcileJitterSeconds("0"),
+	WithReconcileTimeoutSeconds("50"),
 }
 
 // WithKubernetesClient returns Option to set Kubernetes API client.
@@ -67,3 +86,409 @@ func WithDesiredGPUCount(s string) Option {
 		}
 	}
 }
+
+// WithNodeStartupGracePeriodMinutes returns Option to set how long newly created nodes
+// are evaluated but never remediated. 0 disables the grace period.
+func WithNodeStartupGracePeriodMinutes(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			w.nodeStartupGracePeriod = time.Duration(n) * time.Minute
+		} else {
+			slog.Info("NodeStartupGracePeriodMinutes is invalid", "value", s)
+		}
+	}
+}
+
+// WithOptIn returns Option to enable opt-in mode, in which only nodes that are
+// explicitly labelled or annotated as enabled are remediated.
+func WithOptIn(s string) Option {
+	return func(w *watcher) {
+		b, err := strconv.ParseBool(s)
+		if err == nil {
+			w.optIn = b
+		} else {
+			slog.Info("OptIn is invalid", "value", s)
+		}
+	}
+}
+
+// WithSkipCordonedNodes returns Option to set whether nodes cordoned by someone
+// other than node-agent are excluded from remediation.
+func WithSkipCordonedNodes(s string) Option {
+	return func(w *watcher) {
+		b, err := strconv.ParseBool(s)
+		if err == nil {
+			w.skipCordonedNodes = b
+		} else {
+			slog.Info("SkipCordonedNodes is invalid", "value", s)
+		}
+	}
+}
+
+// WithMaintenanceTaintKeys returns Option to set the comma-separated taint keys
+// that exclude a node from remediation while it is under maintenance.
+func WithMaintenanceTaintKeys(s string) Option {
+	return func(w *watcher) {
+		var keys []string
+		for _, key := range strings.Split(s, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		w.maintenanceTaintKeys = keys
+	}
+}
+
+// WithRebootWindows returns Option to set the maintenance windows during which reboots are allowed,
+// as a semicolon-separated list of a cron expression followed by a duration, e.g. "0 2 * * 1-5 4h".
+// Reboots are allowed at any time when no windows are set.
+func WithRebootWindows(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.rebootWindows = s
+		}
+	}
+}
+
+// WithRebootBlackouts returns Option to set the comma-separated blackout periods
+// during which reboots are never allowed, e.g. "2025-12-24T00:00/2025-12-27T00:00".
+func WithRebootBlackouts(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.rebootBlackouts = s
+		}
+	}
+}
+
+// WithRebootWindowTimezone returns Option to set the timezone of maintenance windows and blackout periods.
+func WithRebootWindowTimezone(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.rebootWindowTimezone = s
+		}
+	}
+}
+
+// WithRebootWindowOverrideMinutes returns Option to set how long a node must have been unhealthy
+// before it is rebooted outside of maintenance windows. 0 disables the override.
+func WithRebootWindowOverrideMinutes(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			w.rebootWindowOverride = time.Duration(n) * time.Minute
+		} else {
+			slog.Info("RebootWindowOverrideMinutes is invalid", "value", s)
+		}
+	}
+}
+
+// WithMaxRebootsPerNode returns Option to set how many times a node may be rebooted within
+// the max reboots period before it is quarantined instead. 0 disables the limit.
+func WithMaxRebootsPerNode(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			w.maxRebootsPerNode = n
+		} else {
+			slog.Info("MaxRebootsPerNode is invalid", "value", s)
+		}
+	}
+}
+
+// WithMaxRebootsPeriodMinutes returns Option to set the rolling period the max reboots per node applies to.
+func WithMaxRebootsPeriodMinutes(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n > 0 {
+			w.maxRebootsPeriod = time.Duration(n) * time.Minute
+		} else {
+			slog.Info("MaxRebootsPeriodMinutes is invalid", "value", s)
+		}
+	}
+}
+
+// WithVerificationTimeoutMinutes returns Option to set how long a remediated node has to recover
+// before the remediation is considered failed. 0 uses the reboot time window.
+func WithVerificationTimeoutMinutes(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			w.verificationTimeout = time.Duration(n) * time.Minute
+		} else {
+			slog.Info("VerificationTimeoutMinutes is invalid", "value", s)
+		}
+	}
+}
+
+// WithEventRecorder returns Option to set the recorder of the events node-agent records on nodes.
+func WithEventRecorder(recorder record.EventRecorder) Option {
+	return func(w *watcher) {
+		if recorder != nil {
+			w.eventRecorder = recorder
+		}
+	}
+}
+
+// WithHealthChecks returns Option to add health checks that every node is evaluated with,
+// in addition to the built-in GPU count and Ready checks.
+func WithHealthChecks(checks ...HealthCheck) Option {
+	return func(w *watcher) {
+		for _, check := range checks {
+			if check != nil {
+				w.healthChecks = append(w.healthChecks, check)
+			}
+		}
+	}
+}
+
+// WithCapacityCheckResources returns Option to add a check for each of the comma-separated extended resources,
+// e.g. "nvidia.com/gpu", that finds nodes whose allocatable amount is lower than their capacity unhealthy.
+func WithCapacityCheckResources(s string) Option {
+	return func(w *watcher) {
+		for _, resource := range strings.Split(s, ",") {
+			if resource = strings.TrimSpace(resource); resource != "" {
+				w.healthChecks = append(w.healthChecks, NewResourceCapacityCheck(resource))
+			}
+		}
+	}
+}
+
+// WithDevicePluginNamespace returns Option to set the namespace of the device plugin pods. When it is set,
+// nodes whose device plugin pod is missing or crash looping are unhealthy, and crash looping device plugin
+// pods are restarted before the node is remediated.
+func WithDevicePluginNamespace(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.devicePluginNamespace = s
+		}
+	}
+}
+
+// WithDevicePluginSelector returns Option to set the label selector of the device plugin pods.
+func WithDevicePluginSelector(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.devicePluginSelector = s
+		}
+	}
+}
+
+// WithDevicePluginRestartPeriodMinutes returns Option to set the period the device plugin of a node is restarted at most once in,
+// before its remediation is escalated.
+func WithDevicePluginRestartPeriodMinutes(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n > 0 {
+			w.devicePluginRestartPeriod = time.Duration(n) * time.Minute
+		} else {
+			slog.Info("DevicePluginRestartPeriodMinutes is invalid", "value", s)
+		}
+	}
+}
+
+// WithNotifier returns Option to set the Notifier, which takes precedence over WithWebhookURL.
+func WithNotifier(notifier Notifier) Option {
+	return func(w *watcher) {
+		if notifier != nil {
+			w.notifier = notifier
+		}
+	}
+}
+
+// WithWebhookURL returns Option to set the URL notifications are POSTed to. No notifications are sent when it is empty.
+func WithWebhookURL(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.webhookURL = s
+		}
+	}
+}
+
+// WithWebhookTemplate returns Option to set the text/template that renders the payload of webhook notifications.
+// The payload is the JSON encoded notification when it is empty.
+func WithWebhookTemplate(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.webhookTemplate = s
+		}
+	}
+}
+
+// WithWebhookEvents returns Option to set the comma-separated events webhook notifications are sent for,
+// i.e. unhealthy, remediated, skipped or failed. Notifications are sent for all events when it is empty.
+func WithWebhookEvents(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.webhookEvents = s
+		}
+	}
+}
+
+// WithWebhookRetries returns Option to set how many times a failed webhook notification is retried.
+func WithWebhookRetries(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			w.webhookRetries = n
+		} else {
+			slog.Info("WebhookRetries is invalid", "value", s)
+		}
+	}
+}
+
+// WithApprover returns Option to set the Approver, which takes precedence over WithApprovalURL.
+func WithApprover(approver Approver) Option {
+	return func(w *watcher) {
+		if approver != nil {
+			w.approver = approver
+		}
+	}
+}
+
+// WithApprovalURL returns Option to set the URL every remediation is POSTed to for approval.
+// Remediations are not gated when it is empty.
+func WithApprovalURL(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.approvalURL = s
+		}
+	}
+}
+
+// WithApprovalTimeoutSeconds returns Option to set how long to wait for the answer to an approval request.
+func WithApprovalTimeoutSeconds(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n > 0 {
+			w.approvalTimeout = time.Duration(n) * time.Second
+		} else {
+			slog.Info("ApprovalTimeoutSeconds is invalid", "value", s)
+		}
+	}
+}
+
+// WithApprovalDefault returns Option to set whether remediations are approved ("allow") or denied ("deny")
+// when the approver cannot give an answer, e.g. because it timed out.
+func WithApprovalDefault(s string) Option {
+	return func(w *watcher) {
+		switch strings.ToLower(s) {
+		case "allow":
+			w.approvalDefault = true
+		case "deny":
+			w.approvalDefault = false
+		default:
+			slog.Info("ApprovalDefault is invalid", "value", s)
+		}
+	}
+}
+
+// WithStuckPodCleanup returns Option to set whether pods stuck terminating or in the Unknown phase
+// are force deleted from remediated nodes once they are Ready again, or once the remediation timed out.
+func WithStuckPodCleanup(s string) Option {
+	return func(w *watcher) {
+		b, err := strconv.ParseBool(s)
+		if err == nil {
+			w.stuckPodCleanup = b
+		} else {
+			slog.Info("StuckPodCleanup is invalid", "value", s)
+		}
+	}
+}
+
+// WithStuckPodNamespaces returns Option to limit the stuck pod cleanup to the comma-separated namespaces.
+func WithStuckPodNamespaces(s string) Option {
+	return func(w *watcher) {
+		var namespaces []string
+		for _, ns := range strings.Split(s, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				namespaces = append(namespaces, ns)
+			}
+		}
+		w.stuckPodNamespaces = namespaces
+	}
+}
+
+// WithStuckPodSelector returns Option to limit the stuck pod cleanup to the pods matching the label selector.
+func WithStuckPodSelector(s string) Option {
+	return func(w *watcher) {
+		if s != "" {
+			w.stuckPodSelector = s
+		}
+	}
+}
+
+// WithUnhealthyRules returns Option to add custom rules that find nodes unhealthy, as a JSON array of
+// {"name": ..., "expression": ..., "severity": "critical|warning"} objects with CEL expressions.
+// The rules are compiled by NewWatcher, which fails when any of them is invalid.
+func WithUnhealthyRules(s string) Option {
+	return func(w *watcher) {
+		w.unhealthyRules = s
+	}
+}
+
+// WithRemediation returns Option to select the built-in remediation by name,
+// i.e. hard-reboot, soft-reboot, recycle, delete-node or taint.
+func WithRemediation(name string) Option {
+	return func(w *watcher) {
+		if name != "" {
+			w.remediationName = name
+		}
+	}
+}
+
+// WithRemediator returns Option to set the Remediator, which takes precedence over WithRemediation.
+func WithRemediator(remediator Remediator) Option {
+	return func(w *watcher) {
+		if remediator != nil {
+			w.remediator = remediator
+		}
+	}
+}
+
+// WithReconcileIntervalSeconds returns Option to set how often nodes are reconciled.
+func WithReconcileIntervalSeconds(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n > 0 {
+			w.reconcileInterval = time.Duration(n) * time.Second
+		} else {
+			slog.Info("ReconcileIntervalSeconds is invalid", "value", s)
+		}
+	}
+}
+
+// WithReconcileJitterSeconds returns Option to set the maximum random delay added to the reconcile interval.
+func WithReconcileJitterSeconds(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			w.reconcileJitter = time.Duration(n) * time.Second
+		} else {
+			slog.Info("ReconcileJitterSeconds is invalid", "value", s)
+		}
+	}
+}
+
+// WithReconcileTimeoutSeconds returns Option to set how long a reconcile may take before it is cancelled,
+// and it stops waiting for the Civo API calls it made. A timeout of 0 lets reconciles take as long as they need.
+func WithReconcileTimeoutSeconds(s string) Option {
+	return func(w *watcher) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			w.reconcileTimeout = time.Duration(n) * time.Second
+		} else {
+			slog.Info("ReconcileTimeoutSeconds is invalid", "value", s)
+		}
+	}
+}
+
+// WithHTTPAddress returns Option to set the address the HTTP server listens on.
+// The HTTP server, which exposes metrics, is not started when it is empty.
+func WithHTTPAddress(addr string) Option {
+	return func(w *watcher) {
+		if addr != "" {
+			w.httpAddress = addr
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+// livenessReconcileIntervals is how many reconcile intervals may pass without a reconcile finishing
+// before node-agent is considered wedged, e.g. on a hung API call.
+const livenessReconcileIntervals = 6
+
+// readinessTimeout is how long the readiness probe waits for the Kubernetes and Civo APIs to respond.
+const readinessTimeout = 5 * time.Second
+
+// markReconciled records that a reconcile finished at the time.
+func (w *watcher) markReconciled(now time.Time) {
+	w.lastReconcileMu.Lock()
+	defer w.lastReconcileMu.Unlock()
+	w.lastReconcileAt = now
+}
+
+// checkLiveness returns an error when no reconcile has finished within the liveness reconcile intervals.
+func (w *watcher) checkLiveness(now time.Time) error {
+	w.lastReconcileMu.Lock()
+	defer w.lastReconcileMu.Unlock()
+
+	if w.lastReconcileAt.IsZero() {
+		return errors.New("watcher is not running")
+	}
+	// A reconcile may take up to the reconcile timeout, and the next one starts up to an interval and a jitter later.
+	threshold := max(livenessReconcileIntervals*w.reconcileInterval, w.reconcileTimeout+w.reconcileInterval+w.reconcileJitter)
+	if since := now.Sub(w.lastReconcileAt); since > threshold {
+		return fmt.Errorf("last reconcile finished %s ago", since.Round(time.Second))
+	}
+	return nil
+}
+
+// checkReadiness returns an error when the Kubernetes or Civo client is not initialised or cannot reach its API
+// within the readiness timeout.
+func (w *watcher) checkReadiness(ctx context.Context) error {
+	if w.client == nil {
+		return errors.New("kubernetes client is not initialised")
+	}
+	if w.civoClient == nil {
+		return errors.New("civo client is not initialised")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	if err := w.checkKubernetesAPI(ctx); err != nil {
+		return fmt.Errorf("failed to reach the kubernetes API: %w", err)
+	}
+	_, err := callCivo(ctx, func() (*civogo.KubernetesCluster, error) {
+		return w.civoClient.GetKubernetesCluster(w.clusterID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reach the civo API: %w", err)
+	}
+	return nil
+}
+
+// checkKubernetesAPI gets the version of the Kubernetes API server, which is cancelled with the context.
+// Fake clients have no REST client, so their version is got without the context.
+func (w *watcher) checkKubernetesAPI(ctx context.Context) error {
+	restClient := w.client.Discovery().RESTClient()
+	if restClient == nil {
+		_, err := w.client.Discovery().ServerVersion()
+		return err
+	}
+	return restClient.Get().AbsPath("/version").Do(ctx).Error()
+}
+
+// handleHealthz responds with whether node-agent is alive, i.e. it keeps reconciling.
+func (w *watcher) handleHealthz(rw http.ResponseWriter, _ *http.Request) {
+	writeProbeResponse(rw, "healthz", w.checkLiveness(time.Now()))
+}
+
+// handleReadyz responds with whether node-agent is ready, i.e. it can reach the Kubernetes and Civo APIs.
+func (w *watcher) handleReadyz(rw http.ResponseWriter, req *http.Request) {
+	writeProbeResponse(rw, "readyz", w.checkReadiness(req.Context()))
+}
+
+func writeProbeResponse(rw http.ResponseWriter, probe string, err error) {
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err != nil {
+		slog.Info("Probe failed", "probe", probe, "error", err)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(rw, err.Error())
+		return
+	}
+	fmt.Fprintln(rw, "ok")
+}
@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// quarantinedTaintKey is the taint node-agent puts on nodes that were rebooted too many times.
+	quarantinedTaintKey = "node-agent.civo.com/quarantined"
+	// quarantinedReasonKey is the annotation that records why node-agent quarantined a node.
+	quarantinedReasonKey = "node-agent.civo.com/quarantined-reason"
+)
+
+// recordReboot adds a reboot of the node at t to the reboot history,
+// and forgets about reboots that were before the max reboots period.
+func (w *watcher) recordReboot(nodeName string, t time.Time) {
+	w.rebootHistoryMu.Lock()
+	defer w.rebootHistoryMu.Unlock()
+
+	since := t.Add(-w.maxRebootsPeriod)
+	history := slices.DeleteFunc(w.rebootHistory[nodeName], func(rebootedAt time.Time) bool {
+		return !rebootedAt.After(since)
+	})
+	w.rebootHistory[nodeName] = append(history, t)
+}
+
+// isNodeQuarantined checks if node-agent has quarantined the node.
+func isNodeQuarantined(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == quarantinedTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// quarantineNode cordons and taints the node so that it is left for a human or a replacement action,
+// and records the reason in an annotation. The cordon is marked as applied by node-agent.
+func (w *watcher) quarantineNode(ctx context.Context, name, reason string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := w.client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		if !node.Spec.Unschedulable {
+			node.Spec.Unschedulable = true
+			node.Annotations[cordonedByAgentKey] = "true"
+		}
+		node.Annotations[quarantinedReasonKey] = reason
+		if !isNodeQuarantined(node) {
+			node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+				Key:       quarantinedTaintKey,
+				Value:     "true",
+				Effect:    corev1.TaintEffectNoSchedule,
+				TimeAdded: &metav1.Time{Time: time.Now()},
+			})
+		}
+		_, err = w.client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to quarantine node, nodeName: %s: %w", name, err)
+	}
+
+	slog.Info("Node is quarantined", "node", name, "reason", reason)
+	w.transition(name, nodeEventQuarantined, reason, time.Now())
+	nodesQuarantinedTotal.WithLabelValues(w.clusterID, w.nodePoolID).Inc()
+	return nil
+}
@@ -0,0 +1,258 @@
+package watcher
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRebootHistoryOf(t *testing.T) {
+	now := time.Now()
+
+	type test struct {
+		name    string
+		history []time.Time
+		want    int
+	}
+
+	tests := []test{
+		{
+			name: "Returns 0 when node has never been rebooted",
+			want: 0,
+		},
+		{
+			name: "Returns the number of reboots within the max reboots period",
+			history: []time.Time{
+				now.Add(-30 * time.Hour),
+				now.Add(-20 * time.Hour),
+				now.Add(-time.Hour),
+			},
+			want: 2,
+		},
+		{
+			name: "Returns 0 when all reboots are before the max reboots period",
+			history: []time.Time{
+				now.Add(-30 * time.Hour),
+			},
+			want: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(&FakeClient{}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := w.(*watcher)
+			for _, rebootedAt := range test.history {
+				obj.recordReboot("node-01", rebootedAt)
+			}
+			if got := len(obj.rebootHistoryOf("node-01")); got != test.want {
+				t.Errorf("got = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestQuarantineNode(t *testing.T) {
+	type test struct {
+		name             string
+		node             *corev1.Node
+		wantAgentCordon  bool
+		wantTaintsLength int
+	}
+
+	tests := []test{
+		{
+			name: "Cordons, taints and annotates a schedulable node",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+			},
+			wantAgentCordon:  true,
+			wantTaintsLength: 1,
+		},
+		{
+			name: "Does not mark the cordon as applied by node-agent when node was already cordoned",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+				Spec: corev1.NodeSpec{
+					Unschedulable: true,
+				},
+			},
+			wantAgentCordon:  false,
+			wantTaintsLength: 1,
+		},
+		{
+			name: "Does not add the taint twice",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{
+							Key:    quarantinedTaintKey,
+							Value:  "true",
+							Effect: corev1.TaintEffectNoSchedule,
+						},
+					},
+				},
+			},
+			wantAgentCordon:  true,
+			wantTaintsLength: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset(test.node)),
+				WithCivoClient(&FakeClient{}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := w.(*watcher)
+			if err := obj.quarantineNode(t.Context(), test.node.GetName(), "test reason"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := obj.client.CoreV1().Nodes().Get(t.Context(), test.node.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Spec.Unschedulable {
+				t.Errorf("node is not cordoned")
+			}
+			if !isNodeQuarantined(got) {
+				t.Errorf("node is not quarantined")
+			}
+			if len(got.Spec.Taints) != test.wantTaintsLength {
+				t.Errorf("taints = %v, want %d taints", got.Spec.Taints, test.wantTaintsLength)
+			}
+			if got.Annotations[quarantinedReasonKey] != "test reason" {
+				t.Errorf("reason = %q, want %q", got.Annotations[quarantinedReasonKey], "test reason")
+			}
+			if agentCordon := got.Annotations[cordonedByAgentKey] == "true"; agentCordon != test.wantAgentCordon {
+				t.Errorf("cordoned by agent = %v, want %v", agentCordon, test.wantAgentCordon)
+			}
+		})
+	}
+}
+
+func TestRunQuarantinesNodeAfterMaxReboots(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionFalse,
+				},
+			},
+			Allocatable: corev1.ResourceList{
+				gpuResourceName: resource.MustParse("8"),
+			},
+		},
+	}
+
+	civoClient := newFakeClient()
+	civoClient.FindKubernetesClusterInstanceFunc = func(clusterID, search string) (*civogo.Instance, error) {
+		t.Errorf("FindKubernetesClusterInstance must not be called for a node that reached max reboots")
+		return nil, errors.New("invalid error")
+	}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(civoClient),
+		WithDesiredGPUCount(testNodeDesiredGPUCount),
+		WithMaxRebootsPerNode("2"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	obj.recordReboot(node.GetName(), time.Now().Add(-3*time.Hour))
+	obj.recordReboot(node.GetName(), time.Now().Add(-2*time.Hour))
+
+	if err := obj.run(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := obj.client.CoreV1().Nodes().Get(t.Context(), node.GetName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isNodeQuarantined(got) {
+		t.Errorf("node is not quarantined")
+	}
+}
+
+func TestRunRestoresQuarantinedState(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints: []corev1.Taint{
+				{
+					Key:    quarantinedTaintKey,
+					Effect: corev1.TaintEffectNoSchedule,
+				},
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionFalse,
+				},
+			},
+		},
+	}
+
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(newFakeClient()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	if err := obj.run(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := obj.nodeStateOf(node.GetName()); state != nodeStateQuarantined {
+		t.Errorf("state = %v, want %v", state, nodeStateQuarantined)
+	}
+}
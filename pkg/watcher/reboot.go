@@ -0,0 +1,157 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+// lastRemediationKey is the annotation node-agent sets to the time it last remediated the node,
+// so that the reboot time window is kept across restarts and by the reboot subcommand.
+const lastRemediationKey = "node-agent.civo.com/last-remediation"
+
+// operatorRebootReason is the reason of the remediations requested with Reboot.
+const operatorRebootReason = "reboot was requested by an operator"
+
+// RebootPlan describes a node that an operator is about to reboot, and the safety checks it fails.
+type RebootPlan struct {
+	Node       string `json:"node"`
+	InstanceID string `json:"instanceID,omitempty"`
+	Action     string `json:"action"`
+	// LastRemediation is when node-agent last remediated the node, if known.
+	LastRemediation *time.Time `json:"lastRemediation,omitempty"`
+	// NotReadyNodes are the other nodes of the pool that are not ready.
+	NotReadyNodes []string `json:"notReadyNodes"`
+	// Problems are the safety checks the reboot fails. The reboot should only be forced when it is not empty.
+	Problems []string `json:"problems"`
+}
+
+// PlanReboot checks if the node can be rebooted safely. It returns an error when the node is not in the node pool.
+func (w *watcher) PlanReboot(ctx context.Context, nodeName string) (*RebootPlan, error) {
+	node, err := w.poolNode(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := w.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(w.nodeSelector),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &RebootPlan{
+		Node:          nodeName,
+		InstanceID:    instanceIDFromProviderID(node.Spec.ProviderID),
+		Action:        w.remediator.Name(),
+		NotReadyNodes: []string{},
+		Problems:      []string{},
+	}
+
+	if managed, reason := isNodeManaged(node, w.optIn); !managed {
+		plan.Problems = append(plan.Problems, "node is excluded from remediation: "+reason)
+	}
+	if isNodeQuarantined(node) {
+		plan.Problems = append(plan.Problems, "node is quarantined: "+node.GetAnnotations()[quarantinedReasonKey])
+	}
+
+	w.restoreLastRemediation(node)
+	if v, ok := w.lastRebootCmdTimes.Load(nodeName); ok {
+		lastRemediation := v.(time.Time)
+		plan.LastRemediation = &lastRemediation
+	}
+	if until := w.cooldownUntil(nodeName); until != nil {
+		plan.Problems = append(plan.Problems,
+			fmt.Sprintf("node was remediated at %s, and should not be remediated again before %s",
+				plan.LastRemediation.Format(time.RFC3339), until.Format(time.RFC3339)))
+	}
+
+	for _, n := range nodes.Items {
+		if n.GetName() != nodeName && !isNodeReady(&n) {
+			plan.NotReadyNodes = append(plan.NotReadyNodes, n.GetName())
+		}
+	}
+	if len(plan.NotReadyNodes) > 0 {
+		plan.Problems = append(plan.Problems,
+			fmt.Sprintf("%d other node(s) of the pool are not ready", len(plan.NotReadyNodes)))
+	}
+	return plan, nil
+}
+
+// Reboot remediates the node through the same path as unhealthy nodes, regardless of its health.
+// It does not check if the reboot is safe, which PlanReboot does. It waits for the notifications and events
+// about the remediation to be sent before it returns, and records no more events after, so that the process
+// can exit right away.
+func (w *watcher) Reboot(ctx context.Context, nodeName string) error {
+	defer w.stopRecordingEvents()
+	defer w.notifications.Wait()
+
+	node, err := w.poolNode(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	result, err := w.remediateNode(ctx, node, operatorRebootReason)
+	if err != nil {
+		return err
+	}
+	if result.Skipped {
+		return errors.New("remediation was not issued: " + result.Reason)
+	}
+	return nil
+}
+
+// poolNode returns the node when it is in the node pool.
+func (w *watcher) poolNode(ctx context.Context, nodeName string) (*corev1.Node, error) {
+	node, err := w.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(w.nodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	if !selector.Matches(labels.Set(node.GetLabels())) {
+		return nil, fmt.Errorf("node %s is not in the node pool %s", nodeName, w.nodePoolID)
+	}
+	return node, nil
+}
+
+// restoreLastRemediation loads the time the node was last remediated from its annotation,
+// unless this node-agent has remediated the node since.
+func (w *watcher) restoreLastRemediation(node *corev1.Node) {
+	value := node.GetAnnotations()[lastRemediationKey]
+	if value == "" {
+		return
+	}
+	lastRemediation, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		slog.Info("Last remediation annotation is invalid", "node", node.GetName(), "value", value)
+		return
+	}
+	if v, ok := w.lastRebootCmdTimes.Load(node.GetName()); ok && !v.(time.Time).Before(lastRemediation) {
+		return
+	}
+	w.lastRebootCmdTimes.Store(node.GetName(), lastRemediation)
+}
+
+// annotateLastRemediation records the time the node was remediated on the node.
+func (w *watcher) annotateLastRemediation(ctx context.Context, name string, at time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := w.client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[lastRemediationKey] = at.UTC().Format(time.RFC3339)
+		_, err = w.client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	})
+}
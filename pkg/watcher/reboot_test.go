@@ -0,0 +1,207 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newPoolNode(name string, ready corev1.ConditionStatus, annotations map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+			Annotations: annotations,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: ready,
+				},
+			},
+		},
+	}
+}
+
+func TestPlanReboot(t *testing.T) {
+	type test struct {
+		name              string
+		nodes             []*corev1.Node
+		wantErr           bool
+		wantProblems      int
+		wantNotReady      int
+		wantLastRemediate bool
+	}
+
+	tests := []test{
+		{
+			name: "Returns no problems when node can be rebooted safely",
+			nodes: []*corev1.Node{
+				newPoolNode("node-01", corev1.ConditionFalse, nil),
+				newPoolNode("node-02", corev1.ConditionTrue, nil),
+			},
+		},
+		{
+			name: "Returns a problem when other nodes of the pool are not ready",
+			nodes: []*corev1.Node{
+				newPoolNode("node-01", corev1.ConditionTrue, nil),
+				newPoolNode("node-02", corev1.ConditionFalse, nil),
+			},
+			wantProblems: 1,
+			wantNotReady: 1,
+		},
+		{
+			name: "Returns a problem when node was remediated within the reboot time window",
+			nodes: []*corev1.Node{
+				newPoolNode("node-01", corev1.ConditionFalse, map[string]string{
+					lastRemediationKey: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+				}),
+			},
+			wantProblems:      1,
+			wantLastRemediate: true,
+		},
+		{
+			name: "Returns no problems when node was remediated before the reboot time window",
+			nodes: []*corev1.Node{
+				newPoolNode("node-01", corev1.ConditionFalse, map[string]string{
+					lastRemediationKey: time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
+				}),
+			},
+			wantLastRemediate: true,
+		},
+		{
+			name: "Returns a problem when node is excluded from remediation",
+			nodes: []*corev1.Node{
+				newPoolNode("node-01", corev1.ConditionFalse, map[string]string{
+					disabledKey: "true",
+				}),
+			},
+			wantProblems: 1,
+		},
+		{
+			name: "Returns a problem when node is quarantined",
+			nodes: []*corev1.Node{
+				func() *corev1.Node {
+					node := newPoolNode("node-01", corev1.ConditionFalse, map[string]string{
+						quarantinedReasonKey: "node was rebooted 3 times within 24h0m0s and is still unhealthy",
+					})
+					node.Spec.Taints = []corev1.Taint{{Key: quarantinedTaintKey, Effect: corev1.TaintEffectNoSchedule}}
+					return node
+				}(),
+			},
+			wantProblems: 1,
+		},
+		{
+			name: "Returns error when node is not in the node pool",
+			nodes: []*corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node-01",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Returns error when node does not exist",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, node := range test.nodes {
+				if _, err := client.CoreV1().Nodes().Create(t.Context(), node, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(client),
+				WithCivoClient(&FakeClient{}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plan, err := w.PlanReboot(t.Context(), "node-01")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if len(plan.Problems) != test.wantProblems {
+				t.Errorf("problems = %v, want %d problems", plan.Problems, test.wantProblems)
+			}
+			if len(plan.NotReadyNodes) != test.wantNotReady {
+				t.Errorf("not ready nodes = %v, want %d nodes", plan.NotReadyNodes, test.wantNotReady)
+			}
+			if got := plan.LastRemediation != nil; got != test.wantLastRemediate {
+				t.Errorf("last remediation = %v, want set %v", plan.LastRemediation, test.wantLastRemediate)
+			}
+		})
+	}
+}
+
+func TestReboot(t *testing.T) {
+	node := newPoolNode("node-01", corev1.ConditionTrue, nil)
+	remediator := &countingRemediator{}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(&FakeClient{}),
+		WithRemediator(remediator),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Reboot(t.Context(), node.GetName()); err != nil {
+		t.Fatal(err)
+	}
+	if remediator.calls != 1 {
+		t.Errorf("remediator calls = %d, want 1", remediator.calls)
+	}
+
+	// The events about the remediation are recorded before Reboot returns.
+	events, err := w.(*watcher).client.CoreV1().Events("").List(t.Context(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 1 || events.Items[0].Reason != eventReasonRemediating {
+		t.Errorf("events = %v, want the %s event", events.Items, eventReasonRemediating)
+	}
+
+	got, err := w.(*watcher).client.CoreV1().Nodes().Get(t.Context(), node.GetName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := time.Parse(time.RFC3339, got.Annotations[lastRemediationKey]); err != nil {
+		t.Errorf("last remediation annotation = %q is invalid: %v", got.Annotations[lastRemediationKey], err)
+	}
+
+	// The remediation is now in its cooldown, which a new node-agent learns from the annotation.
+	next, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(w.(*watcher).client),
+		WithCivoClient(&FakeClient{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := next.PlanReboot(t.Context(), node.GetName())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Problems) != 1 {
+		t.Errorf("problems = %v, want the cooldown problem", plan.Problems)
+	}
+}
@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// rebootRequestedKey is the annotation operators set on a node, usually to the current time,
+	// to have node-agent remediate it regardless of its health.
+	rebootRequestedKey = "node-agent.civo.com/reboot-requested"
+	// rebootAcknowledgedKey is the annotation node-agent sets to the value of the reboot-requested
+	// annotation once it has remediated the node, so that every request is handled once.
+	rebootAcknowledgedKey = "node-agent.civo.com/reboot-acknowledged"
+)
+
+// rebootCheckName is the name of the check result that marks a node with a pending reboot request as unhealthy.
+const rebootCheckName = "RebootRequested"
+
+// pendingRebootRequest returns the value of the reboot-requested annotation of the node,
+// and whether the request has not been acknowledged yet.
+func pendingRebootRequest(node *corev1.Node) (string, bool) {
+	requested := node.GetAnnotations()[rebootRequestedKey]
+	if requested == "" || requested == node.GetAnnotations()[rebootAcknowledgedKey] {
+		return "", false
+	}
+	return requested, true
+}
+
+// withRebootRequest adds a critically unhealthy result to the evaluation when the node has a pending reboot request,
+// so that the node goes through the normal remediation flow regardless of its health.
+func withRebootRequest(node *corev1.Node, eval evaluation) evaluation {
+	requested, ok := pendingRebootRequest(node)
+	if !ok {
+		return eval
+	}
+	eval.results = append(eval.results, checkResult{
+		name:    rebootCheckName,
+		verdict: Unhealthy(SeverityCritical, fmt.Sprintf("reboot was requested at %s", requested)),
+	})
+	return eval
+}
+
+// acknowledgeRebootRequest marks the reboot request of the node as handled.
+func (w *watcher) acknowledgeRebootRequest(ctx context.Context, name, requested string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := w.client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[rebootAcknowledgedKey] = requested
+		_, err = w.client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge reboot request, nodeName: %s: %w", name, err)
+	}
+	slog.Info("Reboot request is acknowledged", "node", name, "requested", requested)
+	return nil
+}
@@ -0,0 +1,122 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPendingRebootRequest(t *testing.T) {
+	type test struct {
+		name        string
+		annotations map[string]string
+		wantPending bool
+	}
+
+	tests := []test{
+		{
+			name:        "Returns pending when reboot is requested",
+			annotations: map[string]string{rebootRequestedKey: "2025-01-01T02:14:00Z"},
+			wantPending: true,
+		},
+		{
+			name: "Returns pending when a new reboot is requested after an acknowledged one",
+			annotations: map[string]string{
+				rebootRequestedKey:    "2025-01-02T02:14:00Z",
+				rebootAcknowledgedKey: "2025-01-01T02:14:00Z",
+			},
+			wantPending: true,
+		},
+		{
+			name: "Returns not pending when the reboot request was acknowledged",
+			annotations: map[string]string{
+				rebootRequestedKey:    "2025-01-01T02:14:00Z",
+				rebootAcknowledgedKey: "2025-01-01T02:14:00Z",
+			},
+			wantPending: false,
+		},
+		{
+			name:        "Returns not pending when no reboot is requested",
+			wantPending: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if _, pending := pendingRebootRequest(node); pending != test.wantPending {
+				t.Errorf("pending = %v, want %v", pending, test.wantPending)
+			}
+		})
+	}
+}
+
+func TestRunHandlesRebootRequest(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+			Annotations: map[string]string{
+				rebootRequestedKey: "2025-01-01T02:14:00Z",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-24 * time.Hour)),
+				},
+			},
+			Allocatable: corev1.ResourceList{
+				gpuResourceName: resource.MustParse("8"),
+			},
+		},
+	}
+
+	remediator := &countingRemediator{}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(newFakeClient()),
+		WithDesiredGPUCount(testNodeDesiredGPUCount),
+		WithRemediator(remediator),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	if err := obj.run(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if remediator.calls != 1 {
+		t.Fatalf("remediator calls = %d, want 1", remediator.calls)
+	}
+
+	got, err := obj.client.CoreV1().Nodes().Get(t.Context(), node.GetName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack := got.GetAnnotations()[rebootAcknowledgedKey]; ack != "2025-01-01T02:14:00Z" {
+		t.Errorf("acknowledged = %q, want %q", ack, "2025-01-01T02:14:00Z")
+	}
+
+	// The acknowledged request must not cause another reboot, even after the cooldown.
+	obj.lastRebootCmdTimes.Delete(node.GetName())
+	if err := obj.run(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if remediator.calls != 1 {
+		t.Errorf("remediator calls = %d, want 1", remediator.calls)
+	}
+}
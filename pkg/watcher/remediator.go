@@ -0,0 +1,337 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// Names of the built-in remediations.
+const (
+	RemediationHardReboot = "hard-reboot"
+	RemediationSoftReboot = "soft-reboot"
+	RemediationRecycle    = "recycle"
+	RemediationDeleteNode = "delete-node"
+	RemediationTaint      = "taint"
+)
+
+// unhealthyTaintKey is the taint the taint remediation puts on unhealthy nodes.
+const unhealthyTaintKey = "node-agent.civo.com/unhealthy"
+
+// RemediationResult is what a Remediator did to a node.
+type RemediationResult struct {
+	// InstanceID is the ID of the Civo instance of the node, if it was looked up.
+	InstanceID string
+	// Action describes the action that was taken, e.g. "hard reboot".
+	Action string
+	// Skipped is true when no action was taken, e.g. because the instance is already rebooting.
+	Skipped bool
+	// Reason explains why the action was taken or skipped.
+	Reason string
+	// Lightweight is true when the action does not disrupt the node, e.g. restarting a pod,
+	// so that it does not count towards the max reboots per node.
+	Lightweight bool
+	// Unverifiable is true when whether the node recovers cannot be told after the action,
+	// e.g. because the Node object was deleted, so that the outcome of the remediation is not verified.
+	Unverifiable bool
+	// Replaced is true when the Civo instance of the node was replaced with a new one, e.g. recycled,
+	// so that the node and its instance going away counts as recovered, as the new one may join under another name.
+	Replaced bool
+}
+
+// Remediator takes an action to bring an unhealthy node back to health.
+type Remediator interface {
+	// Name returns the name of the remediation, which is used in logs and reports.
+	Name() string
+	// Remediate takes the action against the node.
+	Remediate(ctx context.Context, node *corev1.Node) (RemediationResult, error)
+}
+
+// skipper is implemented by Remediators that can tell whether they would skip a node without acting on it,
+// so that remediations they would skip are not submitted for approval.
+type skipper interface {
+	// skipReason returns why the node would be skipped, or empty when the Remediator would act on it.
+	skipReason(ctx context.Context, node *corev1.Node) (string, error)
+}
+
+// remediationSkipReason returns why the Remediator would skip the node, or empty when it would act on it
+// or cannot tell without acting.
+func remediationSkipReason(ctx context.Context, r Remediator, node *corev1.Node) (string, error) {
+	s, ok := r.(skipper)
+	if !ok {
+		return "", nil
+	}
+	return s.skipReason(ctx, node)
+}
+
+// newRemediator returns the built-in Remediator with the given name.
+func newRemediator(name string, client kubernetes.Interface, civoClient civogo.Clienter, clusterID string) (Remediator, error) {
+	switch name {
+	case RemediationHardReboot:
+		return NewHardRebootRemediator(civoClient, clusterID), nil
+	case RemediationSoftReboot:
+		return NewSoftRebootRemediator(civoClient, clusterID), nil
+	case RemediationRecycle:
+		return NewRecycleRemediator(civoClient, clusterID), nil
+	case RemediationDeleteNode:
+		return NewDeleteNodeRemediator(client), nil
+	case RemediationTaint:
+		return NewTaintRemediator(client), nil
+	default:
+		return nil, fmt.Errorf("unknown remediation %q, must be one of %s, %s, %s, %s or %s", name,
+			RemediationHardReboot, RemediationSoftReboot, RemediationRecycle, RemediationDeleteNode, RemediationTaint)
+	}
+}
+
+// instanceAction is the action taken against the Civo instance of an unhealthy node.
+type instanceAction int
+
+const (
+	instanceActionReboot instanceAction = iota
+	instanceActionStart
+	instanceActionSkip
+)
+
+// instanceActionFor decides what to do with an instance of an unhealthy node based on
+// its current Civo status, and returns the reason for that decision.
+// Instances that are already transitioning are left alone, stopped instances are started
+// instead of rebooted, and anything else is hard rebooted.
+func instanceActionFor(status string) (instanceAction, string) {
+	switch strings.ToUpper(status) {
+	case instanceStatusRebooting, instanceStatusHardRebooting:
+		return instanceActionSkip, "instance is already rebooting"
+	case instanceStatusBuildPending, instanceStatusBuilding:
+		return instanceActionSkip, "instance is still being built"
+	case instanceStatusStarting:
+		return instanceActionSkip, "instance is already starting"
+	case instanceStatusStopping, instanceStatusShuttingDown:
+		return instanceActionSkip, "instance is being stopped"
+	case instanceStatusDeleting, instanceStatusDeleted:
+		return instanceActionSkip, "instance is being deleted"
+	case instanceStatusStopped, instanceStatusShutoff:
+		return instanceActionStart, "instance is stopped"
+	case instanceStatusActive:
+		return instanceActionReboot, "instance is active"
+	default:
+		return instanceActionReboot, "instance status is unknown"
+	}
+}
+
+// civoRemediator remediates a node through the Civo API, based on the status of its instance.
+type civoRemediator struct {
+	client    civogo.Clienter
+	clusterID string
+	name      string
+	action    string
+	// startStopped is true when stopped instances are started instead of remediated.
+	startStopped bool
+	// replaces is true when the remediation replaces the instance instead of restarting it.
+	replaces  bool
+	remediate func(client civogo.Clienter, clusterID string, instance *civogo.Instance) (*civogo.SimpleResponse, error)
+}
+
+// NewHardRebootRemediator returns a Remediator that hard reboots the Civo instance of the node.
+// Instances that are already transitioning are left alone, and stopped instances are started instead.
+func NewHardRebootRemediator(client civogo.Clienter, clusterID string) Remediator {
+	return &civoRemediator{
+		client:       client,
+		clusterID:    clusterID,
+		name:         RemediationHardReboot,
+		action:       "hard reboot",
+		startStopped: true,
+		remediate: func(client civogo.Clienter, _ string, instance *civogo.Instance) (*civogo.SimpleResponse, error) {
+			return client.HardRebootInstance(instance.ID)
+		},
+	}
+}
+
+// NewSoftRebootRemediator returns a Remediator that soft reboots the Civo instance of the node.
+// Instances that are already transitioning are left alone, and stopped instances are started instead.
+func NewSoftRebootRemediator(client civogo.Clienter, clusterID string) Remediator {
+	return &civoRemediator{
+		client:       client,
+		clusterID:    clusterID,
+		name:         RemediationSoftReboot,
+		action:       "soft reboot",
+		startStopped: true,
+		remediate: func(client civogo.Clienter, _ string, instance *civogo.Instance) (*civogo.SimpleResponse, error) {
+			return client.SoftRebootInstance(instance.ID)
+		},
+	}
+}
+
+// NewRecycleRemediator returns a Remediator that recycles the node, replacing its Civo instance
+// with a new one in the node pool. Instances that are already transitioning are left alone.
+func NewRecycleRemediator(client civogo.Clienter, clusterID string) Remediator {
+	return &civoRemediator{
+		client:    client,
+		clusterID: clusterID,
+		name:      RemediationRecycle,
+		action:    "recycle",
+		replaces:  true,
+		remediate: func(client civogo.Clienter, clusterID string, instance *civogo.Instance) (*civogo.SimpleResponse, error) {
+			return client.RecycleKubernetesCluster(clusterID, instance.Hostname)
+		},
+	}
+}
+
+func (r *civoRemediator) Name() string {
+	return r.name
+}
+
+// findInstance finds the Civo instance of the node.
+func (r *civoRemediator) findInstance(ctx context.Context, nodeName string) (*civogo.Instance, error) {
+	return callCivo(ctx, func() (*civogo.Instance, error) {
+		return r.client.FindKubernetesClusterInstance(r.clusterID, nodeName)
+	})
+}
+
+func (r *civoRemediator) skipReason(ctx context.Context, node *corev1.Node) (string, error) {
+	instance, err := r.findInstance(ctx, node.GetName())
+	if err != nil {
+		return "", fmt.Errorf("failed to find instance, clusterID: %s, nodeName: %s: %w", r.clusterID, node.GetName(), err)
+	}
+	if action, reason := instanceActionFor(instance.Status); action == instanceActionSkip {
+		return reason, nil
+	}
+	return "", nil
+}
+
+func (r *civoRemediator) Remediate(ctx context.Context, node *corev1.Node) (RemediationResult, error) {
+	name := node.GetName()
+	instance, err := r.findInstance(ctx, name)
+	if err != nil {
+		return RemediationResult{}, fmt.Errorf("failed to find instance, clusterID: %s, nodeName: %s: %w", r.clusterID, name, err)
+	}
+	if instance.Hostname == "" {
+		instance.Hostname = name
+	}
+
+	action, reason := instanceActionFor(instance.Status)
+	switch {
+	case action == instanceActionSkip:
+		slog.Info("Skipping remediation because of the current instance status",
+			"instanceID", instance.ID,
+			"node", name,
+			"status", instance.Status,
+			"reason", reason)
+		return RemediationResult{
+			InstanceID: instance.ID,
+			Action:     r.action,
+			Skipped:    true,
+			Reason:     reason,
+		}, nil
+	case action == instanceActionStart && r.startStopped:
+		_, err := callCivo(ctx, func() (*civogo.SimpleResponse, error) {
+			return r.client.StartInstance(instance.ID)
+		})
+		if err != nil {
+			return RemediationResult{}, fmt.Errorf("failed to start instance, clusterID: %s, instanceID: %s: %w", r.clusterID, instance.ID, err)
+		}
+		slog.Info("Instance is starting", "instanceID", instance.ID, "node", name, "status", instance.Status, "reason", reason)
+		return RemediationResult{
+			InstanceID: instance.ID,
+			Action:     "start",
+			Reason:     reason,
+		}, nil
+	default:
+		_, err := callCivo(ctx, func() (*civogo.SimpleResponse, error) {
+			return r.remediate(r.client, r.clusterID, instance)
+		})
+		if err != nil {
+			return RemediationResult{}, fmt.Errorf("failed to %s instance, clusterID: %s, instanceID: %s: %w", r.action, r.clusterID, instance.ID, err)
+		}
+		slog.Info("Instance is being remediated", "action", r.action, "instanceID", instance.ID, "node", name, "status", instance.Status, "reason", reason)
+		return RemediationResult{
+			InstanceID: instance.ID,
+			Action:     r.action,
+			Reason:     reason,
+			Replaced:   r.replaces,
+		}, nil
+	}
+}
+
+type deleteNodeRemediator struct {
+	client kubernetes.Interface
+}
+
+// NewDeleteNodeRemediator returns a Remediator that deletes the Node object from Kubernetes,
+// without touching its Civo instance. As the node is gone, its recovery is not verified.
+func NewDeleteNodeRemediator(client kubernetes.Interface) Remediator {
+	return &deleteNodeRemediator{
+		client: client,
+	}
+}
+
+func (r *deleteNodeRemediator) Name() string {
+	return RemediationDeleteNode
+}
+
+func (r *deleteNodeRemediator) Remediate(ctx context.Context, node *corev1.Node) (RemediationResult, error) {
+	if err := r.client.CoreV1().Nodes().Delete(ctx, node.GetName(), metav1.DeleteOptions{}); err != nil {
+		return RemediationResult{}, fmt.Errorf("failed to delete node, nodeName: %s: %w", node.GetName(), err)
+	}
+	slog.Info("Node object is deleted", "node", node.GetName())
+	return RemediationResult{
+		InstanceID:   instanceIDFromProviderID(node.Spec.ProviderID),
+		Action:       "delete node",
+		Reason:       "node is unhealthy",
+		Unverifiable: true,
+	}, nil
+}
+
+type taintRemediator struct {
+	client kubernetes.Interface
+}
+
+// NewTaintRemediator returns a Remediator that only taints the node as unhealthy,
+// so that no new pods are scheduled on it, and leaves the rest to a human or another controller.
+func NewTaintRemediator(client kubernetes.Interface) Remediator {
+	return &taintRemediator{
+		client: client,
+	}
+}
+
+func (r *taintRemediator) Name() string {
+	return RemediationTaint
+}
+
+func (r *taintRemediator) Remediate(ctx context.Context, node *corev1.Node) (RemediationResult, error) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		n, err := r.client.CoreV1().Nodes().Get(ctx, node.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, taint := range n.Spec.Taints {
+			if taint.Key == unhealthyTaintKey {
+				return nil
+			}
+		}
+		n.Spec.Taints = append(n.Spec.Taints, corev1.Taint{
+			Key:       unhealthyTaintKey,
+			Value:     "true",
+			Effect:    corev1.TaintEffectNoSchedule,
+			TimeAdded: &metav1.Time{Time: time.Now()},
+		})
+		_, err = r.client.CoreV1().Nodes().Update(ctx, n, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return RemediationResult{}, fmt.Errorf("failed to taint node, nodeName: %s: %w", node.GetName(), err)
+	}
+	slog.Info("Node is tainted as unhealthy", "node", node.GetName())
+	return RemediationResult{
+		InstanceID:  instanceIDFromProviderID(node.Spec.ProviderID),
+		Action:      "taint",
+		Reason:      "node is unhealthy",
+		Lightweight: true,
+	}, nil
+}
@@ -0,0 +1,272 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewRemediator(t *testing.T) {
+	type test struct {
+		name     string
+		remedy   string
+		wantName string
+		wantErr  bool
+	}
+
+	tests := []test{
+		{
+			name:     "Returns hard reboot remediator",
+			remedy:   RemediationHardReboot,
+			wantName: RemediationHardReboot,
+		},
+		{
+			name:     "Returns soft reboot remediator",
+			remedy:   RemediationSoftReboot,
+			wantName: RemediationSoftReboot,
+		},
+		{
+			name:     "Returns recycle remediator",
+			remedy:   RemediationRecycle,
+			wantName: RemediationRecycle,
+		},
+		{
+			name:     "Returns delete node remediator",
+			remedy:   RemediationDeleteNode,
+			wantName: RemediationDeleteNode,
+		},
+		{
+			name:     "Returns taint remediator",
+			remedy:   RemediationTaint,
+			wantName: RemediationTaint,
+		},
+		{
+			name:    "Returns an error when remediation is unknown",
+			remedy:  "power-cycle",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := newRemediator(test.remedy, fake.NewSimpleClientset(), &FakeClient{}, testClusterID)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && r.Name() != test.wantName {
+				t.Errorf("name = %s, want %s", r.Name(), test.wantName)
+			}
+		})
+	}
+}
+
+func TestCivoRemediators(t *testing.T) {
+	type test struct {
+		name         string
+		remediator   func(*FakeClient) Remediator
+		status       string
+		wantCalls    []string
+		wantSkip     bool
+		wantReplaced bool
+	}
+
+	newFake := func(calls *[]string) *FakeClient {
+		return &FakeClient{
+			HardRebootInstanceFunc: func(id string) (*civogo.SimpleResponse, error) {
+				*calls = append(*calls, "hard-reboot:"+id)
+				return new(civogo.SimpleResponse), nil
+			},
+			SoftRebootInstanceFunc: func(id string) (*civogo.SimpleResponse, error) {
+				*calls = append(*calls, "soft-reboot:"+id)
+				return new(civogo.SimpleResponse), nil
+			},
+			StartInstanceFunc: func(id string) (*civogo.SimpleResponse, error) {
+				*calls = append(*calls, "start:"+id)
+				return new(civogo.SimpleResponse), nil
+			},
+			RecycleKubernetesClusterFunc: func(id, hostname string) (*civogo.SimpleResponse, error) {
+				*calls = append(*calls, "recycle:"+hostname)
+				return new(civogo.SimpleResponse), nil
+			},
+		}
+	}
+
+	tests := []test{
+		{
+			name: "Hard reboot remediator hard reboots an active instance",
+			remediator: func(c *FakeClient) Remediator {
+				return NewHardRebootRemediator(c, testClusterID)
+			},
+			status:    "ACTIVE",
+			wantCalls: []string{"hard-reboot:instance-01"},
+		},
+		{
+			name: "Soft reboot remediator soft reboots an active instance",
+			remediator: func(c *FakeClient) Remediator {
+				return NewSoftRebootRemediator(c, testClusterID)
+			},
+			status:    "ACTIVE",
+			wantCalls: []string{"soft-reboot:instance-01"},
+		},
+		{
+			name: "Soft reboot remediator starts a stopped instance",
+			remediator: func(c *FakeClient) Remediator {
+				return NewSoftRebootRemediator(c, testClusterID)
+			},
+			status:    "SHUTOFF",
+			wantCalls: []string{"start:instance-01"},
+		},
+		{
+			name: "Recycle remediator recycles an active instance by hostname",
+			remediator: func(c *FakeClient) Remediator {
+				return NewRecycleRemediator(c, testClusterID)
+			},
+			status:       "ACTIVE",
+			wantCalls:    []string{"recycle:node-01"},
+			wantReplaced: true,
+		},
+		{
+			name: "Recycle remediator recycles a stopped instance instead of starting it",
+			remediator: func(c *FakeClient) Remediator {
+				return NewRecycleRemediator(c, testClusterID)
+			},
+			status:       "SHUTOFF",
+			wantCalls:    []string{"recycle:node-01"},
+			wantReplaced: true,
+		},
+		{
+			name: "Recycle remediator skips an instance that is being deleted",
+			remediator: func(c *FakeClient) Remediator {
+				return NewRecycleRemediator(c, testClusterID)
+			},
+			status:   "DELETING",
+			wantSkip: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls []string
+			client := newFake(&calls)
+			client.FindKubernetesClusterInstanceFunc = func(clusterID, search string) (*civogo.Instance, error) {
+				return &civogo.Instance{ID: "instance-01", Hostname: search, Status: test.status}, nil
+			}
+
+			result, err := test.remediator(client).Remediate(t.Context(), &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Skipped != test.wantSkip {
+				t.Errorf("skipped = %v, want %v", result.Skipped, test.wantSkip)
+			}
+			if result.Replaced != test.wantReplaced {
+				t.Errorf("replaced = %v, want %v", result.Replaced, test.wantReplaced)
+			}
+			if result.InstanceID != "instance-01" {
+				t.Errorf("instanceID = %s, want instance-01", result.InstanceID)
+			}
+			if len(calls) != len(test.wantCalls) {
+				t.Fatalf("calls = %v, want %v", calls, test.wantCalls)
+			}
+			for i := range calls {
+				if calls[i] != test.wantCalls[i] {
+					t.Errorf("calls = %v, want %v", calls, test.wantCalls)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteNodeRemediator(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: civoProviderIDPrefix + "instance-01",
+		},
+	}
+	client := fake.NewSimpleClientset(node)
+
+	result, err := NewDeleteNodeRemediator(client).Remediate(t.Context(), node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.InstanceID != "instance-01" {
+		t.Errorf("instance ID = %q, want %q", result.InstanceID, "instance-01")
+	}
+	if !result.Unverifiable {
+		t.Errorf("unverifiable = false, want true")
+	}
+
+	_, err = client.CoreV1().Nodes().Get(t.Context(), node.GetName(), metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("error = %v, want not found", err)
+	}
+}
+
+func TestTaintRemediator(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+		},
+	}
+	client := fake.NewSimpleClientset(node)
+	r := NewTaintRemediator(client)
+
+	for range 2 {
+		result, err := r.Remediate(t.Context(), node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Lightweight {
+			t.Errorf("lightweight = false, want true")
+		}
+	}
+
+	got, err := client.CoreV1().Nodes().Get(t.Context(), node.GetName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Spec.Taints) != 1 || got.Spec.Taints[0].Key != unhealthyTaintKey {
+		t.Errorf("taints = %v, want a single %s taint", got.Spec.Taints, unhealthyTaintKey)
+	}
+}
+
+func TestRemediateNodeDoesNotVerifyDeletedNodes(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+		},
+	}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(&FakeClient{}),
+		WithRemediation(RemediationDeleteNode),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	if _, err := obj.remediateNode(t.Context(), node, "node is not ready"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := obj.verifications[node.GetName()]; ok {
+		t.Errorf("remediation of deleted node is verified")
+	}
+
+	obj.forgetNodes(map[string]bool{})
+	if _, ok := obj.nodeStateOf(node.GetName()); ok {
+		t.Errorf("state of deleted node was not forgotten")
+	}
+}
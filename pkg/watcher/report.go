@@ -0,0 +1,103 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Report is the result of a single evaluation of the node pool.
+type Report struct {
+	ClusterID  string `json:"clusterID"`
+	NodePoolID string `json:"nodePoolID"`
+	// PauseReason is why remediation is paused, or empty when it is not.
+	PauseReason string       `json:"pauseReason,omitempty"`
+	Nodes       []NodeReport `json:"nodes"`
+}
+
+// Healthy checks if no node in the report needs remediation.
+func (r *Report) Healthy() bool {
+	for _, n := range r.Nodes {
+		if !n.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// NodeReport is the health of a node and what node-agent would do with it.
+type NodeReport struct {
+	Name       string        `json:"name"`
+	InstanceID string        `json:"instanceID,omitempty"`
+	Healthy    bool          `json:"healthy"`
+	Checks     []CheckReport `json:"checks"`
+	// Action is what node-agent would do with the node, i.e. none, skip, queue, quarantine or remediate.
+	Action string `json:"action"`
+	// Rule is the rule that produced the action, e.g. cooldown or maintenance-window.
+	Rule   string `json:"rule"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CheckReport is the Verdict of a HealthCheck for a node.
+type CheckReport struct {
+	Name     string `json:"name"`
+	Healthy  bool   `json:"healthy"`
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+}
+
+// Check evaluates every node in the pool once and reports what would be done with it,
+// without remediating any node.
+func (w *watcher) Check(ctx context.Context) (*Report, error) {
+	nodes, err := w.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(w.nodeSelector),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pauseReason, err := w.clusterPauseReason(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = w.prefetchChecks(ctx)
+	now := time.Now()
+	report := &Report{
+		ClusterID:   w.clusterID,
+		NodePoolID:  w.nodePoolID,
+		PauseReason: pauseReason,
+		Nodes:       make([]NodeReport, 0, len(nodes.Items)),
+	}
+	for _, node := range nodes.Items {
+		report.Nodes = append(report.Nodes, w.nodeReport(ctx, &node, now, pauseReason))
+	}
+	return report, nil
+}
+
+// nodeReport evaluates the node and decides what to do with it, without acting on the decision.
+func (w *watcher) nodeReport(ctx context.Context, node *corev1.Node, now time.Time, pauseReason string) NodeReport {
+	w.restoreLastRemediation(node)
+	eval := withRebootRequest(node, w.evaluateNode(ctx, node))
+	d := w.decide(node, eval, now, pauseReason)
+
+	checks := make([]CheckReport, 0, len(eval.results))
+	for _, r := range eval.results {
+		checks = append(checks, CheckReport{
+			Name:     r.name,
+			Healthy:  r.verdict.Healthy,
+			Severity: r.verdict.Severity.String(),
+			Reason:   r.verdict.Reason,
+		})
+	}
+	return NodeReport{
+		Name:       node.GetName(),
+		InstanceID: instanceIDFromProviderID(node.Spec.ProviderID),
+		Healthy:    !eval.needsRemediation(),
+		Checks:     checks,
+		Action:     string(d.action),
+		Rule:       d.rule,
+		Reason:     d.reason,
+	}
+}
@@ -0,0 +1,129 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheck(t *testing.T) {
+	newNode := func(name string, ready corev1.ConditionStatus, gpus string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-24 * time.Hour)),
+				Labels: map[string]string{
+					nodePoolLabelKey: testNodePoolID,
+				},
+			},
+			Spec: corev1.NodeSpec{
+				ProviderID: civoProviderIDPrefix + name,
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             ready,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+					},
+				},
+				Allocatable: corev1.ResourceList{
+					gpuResourceName: resource.MustParse(gpus),
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name        string
+		nodes       []*corev1.Node
+		civoClient  *FakeClient
+		wantHealthy bool
+		wantActions map[string]decisionAction
+		wantPaused  bool
+	}
+
+	tests := []test{
+		{
+			name:        "Reports healthy when all nodes are healthy",
+			nodes:       []*corev1.Node{newNode("node-01", corev1.ConditionTrue, "8")},
+			civoClient:  newFakeClient(),
+			wantHealthy: true,
+			wantActions: map[string]decisionAction{"node-01": decisionNone},
+		},
+		{
+			name: "Reports unhealthy node and the remediation that would be taken",
+			nodes: []*corev1.Node{
+				newNode("node-01", corev1.ConditionTrue, "8"),
+				newNode("node-02", corev1.ConditionFalse, "8"),
+			},
+			civoClient:  newFakeClient(),
+			wantHealthy: false,
+			wantActions: map[string]decisionAction{"node-01": decisionNone, "node-02": decisionRemediate},
+		},
+		{
+			name:  "Reports skip when remediation is paused",
+			nodes: []*corev1.Node{newNode("node-01", corev1.ConditionTrue, "7")},
+			civoClient: &FakeClient{
+				GetKubernetesClusterFunc: func(id string) (*civogo.KubernetesCluster, error) {
+					cluster := newSteadyCluster(id)
+					cluster.Status = "UPGRADING"
+					return cluster, nil
+				},
+			},
+			wantHealthy: false,
+			wantActions: map[string]decisionAction{"node-01": decisionSkip},
+			wantPaused:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, node := range test.nodes {
+				if _, err := client.CoreV1().Nodes().Create(t.Context(), node, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(client),
+				WithCivoClient(test.civoClient),
+				WithDesiredGPUCount(testNodeDesiredGPUCount),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := w.Check(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := report.Healthy(); got != test.wantHealthy {
+				t.Errorf("healthy = %v, want %v", got, test.wantHealthy)
+			}
+			if got := report.PauseReason != ""; got != test.wantPaused {
+				t.Errorf("paused = %v (%q), want %v", got, report.PauseReason, test.wantPaused)
+			}
+			if len(report.Nodes) != len(test.wantActions) {
+				t.Fatalf("nodes = %d, want %d", len(report.Nodes), len(test.wantActions))
+			}
+			for _, n := range report.Nodes {
+				if n.Action != string(test.wantActions[n.Name]) {
+					t.Errorf("%s: action = %q, want %q (%s)", n.Name, n.Action, test.wantActions[n.Name], n.Rule)
+				}
+				if n.InstanceID != n.Name {
+					t.Errorf("%s: instance ID = %q, want %q", n.Name, n.InstanceID, n.Name)
+				}
+				if len(n.Checks) == 0 {
+					t.Errorf("%s: checks are empty", n.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package watcher
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const serverShutdownTimeout = 5 * time.Second
+
+// serve starts the HTTP server of the watcher and blocks until the context is cancelled.
+func (w *watcher) serve(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("GET /status", w.handleStatus)
+	mux.HandleFunc("GET /healthz", w.handleHealthz)
+	mux.HandleFunc("GET /readyz", w.handleReadyz)
+
+	srv := &http.Server{
+		Addr:              w.httpAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("Starting the HTTP server", "address", w.httpAddress)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("failed to serve HTTP on %q: %w", w.httpAddress, err)
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down the HTTP server: %w", err)
+	}
+	return nil
+}
+
+// statusResponse is the response of the status endpoint.
+type statusResponse struct {
+	ClusterID  string               `json:"clusterID"`
+	NodePoolID string               `json:"nodePoolID"`
+	Nodes      []nodeStatusResponse `json:"nodes"`
+}
+
+type nodeStatusResponse struct {
+	Name          string               `json:"name"`
+	InstanceID    string               `json:"instanceID,omitempty"`
+	State         nodeState            `json:"state"`
+	Since         time.Time            `json:"since"`
+	CheckedAt     *time.Time           `json:"checkedAt,omitempty"`
+	Checks        []checkResponse      `json:"checks"`
+	Decision      *decisionResponse    `json:"decision,omitempty"`
+	Approval      *approvalResponse    `json:"approval,omitempty"`
+	CooldownUntil *time.Time           `json:"cooldownUntil,omitempty"`
+	Reboots       []time.Time          `json:"reboots"`
+	Transitions   []transitionResponse `json:"transitions"`
+}
+
+type checkResponse struct {
+	Name     string `json:"name"`
+	Healthy  bool   `json:"healthy"`
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+}
+
+type approvalResponse struct {
+	Approved bool      `json:"approved"`
+	Reason   string    `json:"reason,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+type decisionResponse struct {
+	Action decisionAction `json:"action"`
+	Rule   string         `json:"rule"`
+	Reason string         `json:"reason,omitempty"`
+}
+
+type transitionResponse struct {
+	From   nodeState `json:"from"`
+	To     nodeState `json:"to"`
+	Event  nodeEvent `json:"event"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// handleStatus responds with where each watched node is in its remediation lifecycle,
+// the results of its latest evaluation, the decision made on it and its reboot history.
+func (w *watcher) handleStatus(rw http.ResponseWriter, _ *http.Request) {
+	resp := statusResponse{
+		ClusterID:  w.clusterID,
+		NodePoolID: w.nodePoolID,
+		Nodes:      w.nodeStatusResponses(),
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(resp); err != nil {
+		slog.Error("Failed to write status response", "error", err)
+	}
+}
+
+// cooldownUntil returns when the node can be remediated again after its last remediation,
+// or nil when it is not cooling down.
+func (w *watcher) cooldownUntil(nodeName string) *time.Time {
+	v, _ := w.lastRebootCmdTimes.Load(nodeName)
+	lastRemediation, ok := v.(time.Time)
+	if !ok {
+		return nil
+	}
+	until := lastRemediation.Add(w.rebootTimeWindowMinutes * time.Minute)
+	if until.Before(time.Now()) {
+		return nil
+	}
+	return &until
+}
+
+// rebootHistoryOf returns the reboots of the node within the max reboots period.
+func (w *watcher) rebootHistoryOf(nodeName string) []time.Time {
+	w.rebootHistoryMu.Lock()
+	defer w.rebootHistoryMu.Unlock()
+
+	since := time.Now().Add(-w.maxRebootsPeriod)
+	history := make([]time.Time, 0, len(w.rebootHistory[nodeName]))
+	for _, t := range w.rebootHistory[nodeName] {
+		if t.After(since) {
+			history = append(history, t)
+		}
+	}
+	return history
+}
+
+func (w *watcher) nodeStatusResponses() []nodeStatusResponse {
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+
+	nodes := make([]nodeStatusResponse, 0, len(w.nodeStatuses))
+	for name, status := range w.nodeStatuses {
+		transitions := make([]transitionResponse, 0, len(status.transitions))
+		for _, t := range status.transitions {
+			transitions = append(transitions, transitionResponse{
+				From:   t.from,
+				To:     t.to,
+				Event:  t.event,
+				Reason: t.reason,
+				At:     t.at,
+			})
+		}
+		checks := make([]checkResponse, 0, len(status.checks))
+		for _, c := range status.checks {
+			checks = append(checks, checkResponse{
+				Name:     c.name,
+				Healthy:  c.verdict.Healthy,
+				Severity: c.verdict.Severity.String(),
+				Reason:   c.verdict.Reason,
+			})
+		}
+		resp := nodeStatusResponse{
+			Name:          name,
+			InstanceID:    status.instanceID,
+			State:         status.state,
+			Since:         status.since,
+			Checks:        checks,
+			CooldownUntil: w.cooldownUntil(name),
+			Reboots:       w.rebootHistoryOf(name),
+			Transitions:   transitions,
+		}
+		if !status.checkedAt.IsZero() {
+			resp.CheckedAt = &status.checkedAt
+			resp.Decision = &decisionResponse{
+				Action: status.decision.action,
+				Rule:   status.decision.rule,
+				Reason: status.decision.reason,
+			}
+		}
+		if status.approval != nil {
+			resp.Approval = &approvalResponse{
+				Approved: status.approval.Approved,
+				Reason:   status.approval.Reason,
+				At:       status.approvedAt,
+			}
+		}
+		nodes = append(nodes, resp)
+	}
+	slices.SortFunc(nodes, func(a, b nodeStatusResponse) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return nodes
+}
@@ -0,0 +1,244 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestHandleStatus(t *testing.T) {
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(&FakeClient{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := w.(*watcher)
+
+	now := time.Now()
+	obj.transition("node-02", nodeEventObservedHealthy, "test", now)
+	obj.transition("node-01", nodeEventObservedUnhealthy, "not ready", now)
+	obj.recordDecision(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-01"},
+			Spec:       corev1.NodeSpec{ProviderID: "civo://instance-01"},
+		},
+		evaluation{results: []checkResult{{name: "Ready", verdict: Unhealthy(SeverityCritical, "node is not ready")}}},
+		decision{action: decisionSkip, rule: ruleCooldown, reason: "node was remediated recently"},
+		now,
+	)
+	obj.lastRebootCmdTimes.Store("node-01", now.Add(-time.Minute))
+	obj.recordReboot("node-01", now.Add(-time.Minute))
+
+	rec := httptest.NewRecorder()
+	obj.handleStatus(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("content type = %s, want application/json", got)
+	}
+
+	var resp statusResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ClusterID != testClusterID || resp.NodePoolID != testNodePoolID {
+		t.Errorf("cluster = %s/%s, want %s/%s", resp.ClusterID, resp.NodePoolID, testClusterID, testNodePoolID)
+	}
+	if len(resp.Nodes) != 2 {
+		t.Fatalf("nodes = %v, want 2 nodes", resp.Nodes)
+	}
+	if resp.Nodes[0].Name != "node-01" || resp.Nodes[0].State != nodeStateSuspect {
+		t.Errorf("nodes[0] = %s/%s, want node-01/%s", resp.Nodes[0].Name, resp.Nodes[0].State, nodeStateSuspect)
+	}
+	if len(resp.Nodes[0].Transitions) != 1 || resp.Nodes[0].Transitions[0].Reason != "not ready" {
+		t.Errorf("nodes[0].transitions = %v, want one transition with reason %q", resp.Nodes[0].Transitions, "not ready")
+	}
+	if resp.Nodes[0].InstanceID != "instance-01" {
+		t.Errorf("nodes[0].instanceID = %s, want instance-01", resp.Nodes[0].InstanceID)
+	}
+	if len(resp.Nodes[0].Checks) != 1 || resp.Nodes[0].Checks[0].Healthy || resp.Nodes[0].Checks[0].Severity != "critical" {
+		t.Errorf("nodes[0].checks = %v, want one critical unhealthy check", resp.Nodes[0].Checks)
+	}
+	if d := resp.Nodes[0].Decision; d == nil || d.Action != decisionSkip || d.Rule != ruleCooldown {
+		t.Errorf("nodes[0].decision = %v, want %s/%s", d, decisionSkip, ruleCooldown)
+	}
+	if resp.Nodes[0].CooldownUntil == nil {
+		t.Error("nodes[0].cooldownUntil is not set")
+	}
+	if len(resp.Nodes[0].Reboots) != 1 {
+		t.Errorf("nodes[0].reboots = %v, want 1 reboot", resp.Nodes[0].Reboots)
+	}
+	if resp.Nodes[1].Decision != nil || resp.Nodes[1].CooldownUntil != nil || len(resp.Nodes[1].Reboots) != 0 {
+		t.Errorf("nodes[1] = %+v, want no decision, cooldown or reboots", resp.Nodes[1])
+	}
+	if resp.Nodes[1].Name != "node-02" || resp.Nodes[1].State != nodeStateHealthy {
+		t.Errorf("nodes[1] = %s/%s, want node-02/%s", resp.Nodes[1].Name, resp.Nodes[1].State, nodeStateHealthy)
+	}
+}
+
+func TestHandleHealthz(t *testing.T) {
+	now := time.Now()
+
+	type test struct {
+		name             string
+		lastReconcileAt  time.Time
+		reconcileTimeout string
+		wantCode         int
+	}
+
+	tests := []test{
+		{
+			name:            "Returns OK when a reconcile finished recently",
+			lastReconcileAt: now.Add(-10 * time.Second),
+			wantCode:        http.StatusOK,
+		},
+		{
+			name:            "Returns unavailable when no reconcile finished for too long",
+			lastReconcileAt: now.Add(-time.Hour),
+			wantCode:        http.StatusServiceUnavailable,
+		},
+		{
+			name:            "Returns unavailable when no reconcile finished within the default reconcile timeout",
+			lastReconcileAt: now.Add(-(livenessReconcileIntervals + 1) * 10 * time.Second),
+			wantCode:        http.StatusServiceUnavailable,
+		},
+		{
+			name:             "Returns OK when a reconcile may still be running within the reconcile timeout",
+			lastReconcileAt:  now.Add(-(livenessReconcileIntervals + 1) * 10 * time.Second),
+			reconcileTimeout: "300",
+			wantCode:         http.StatusOK,
+		},
+		{
+			name:     "Returns unavailable when the watcher is not running",
+			wantCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := []Option{
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(&FakeClient{}),
+			}
+			if test.reconcileTimeout != "" {
+				opts = append(opts, WithReconcileTimeoutSeconds(test.reconcileTimeout))
+			}
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				opts...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			obj := w.(*watcher)
+			obj.lastReconcileAt = test.lastReconcileAt
+
+			rec := httptest.NewRecorder()
+			obj.handleHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if rec.Code != test.wantCode {
+				t.Errorf("status code = %d, want %d: %s", rec.Code, test.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleReadyz(t *testing.T) {
+	type test struct {
+		name       string
+		clusterErr error
+		wantCode   int
+	}
+
+	tests := []test{
+		{
+			name:     "Returns OK when both APIs are reachable",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "Returns unavailable when the Civo API is not reachable",
+			clusterErr: errors.New("connection refused"),
+			wantCode:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			civoClient := &FakeClient{
+				GetKubernetesClusterFunc: func(id string) (*civogo.KubernetesCluster, error) {
+					if test.clusterErr != nil {
+						return nil, test.clusterErr
+					}
+					return newSteadyCluster(id), nil
+				},
+			}
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(civoClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			w.(*watcher).handleReadyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != test.wantCode {
+				t.Errorf("status code = %d, want %d: %s", rec.Code, test.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleReadyzReturnsWhenKubernetesAPIHangs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer srv.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(client),
+		WithCivoClient(newFakeClient()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		w.(*watcher).handleReadyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx))
+		done <- rec
+	}()
+
+	select {
+	case rec := <-done:
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("status code = %d, want %d: %s", rec.Code, http.StatusServiceUnavailable, rec.Body.String())
+		}
+	case <-time.After(readinessTimeout):
+		t.Fatal("readyz did not return when the request was cancelled")
+	}
+}
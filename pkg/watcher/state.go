@@ -0,0 +1,286 @@
+package watcher
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// civoProviderIDPrefix is the prefix of the provider ID of nodes backed by Civo instances.
+const civoProviderIDPrefix = "civo://"
+
+// maxStateTransitions is the number of most recent transitions kept for each node.
+const maxStateTransitions = 20
+
+// nodeState is where a node is in its remediation lifecycle.
+type nodeState string
+
+const (
+	nodeStateHealthy     nodeState = "Healthy"
+	nodeStateSuspect     nodeState = "Suspect"
+	nodeStateRemediating nodeState = "Remediating"
+	nodeStateVerifying   nodeState = "Verifying"
+	nodeStateRecovered   nodeState = "Recovered"
+	nodeStateFailed      nodeState = "Failed"
+	nodeStateQuarantined nodeState = "Quarantined"
+)
+
+// nodeEvent is something that happened to a node which may move it to another state.
+type nodeEvent string
+
+const (
+	nodeEventObservedHealthy    nodeEvent = "ObservedHealthy"
+	nodeEventObservedUnhealthy  nodeEvent = "ObservedUnhealthy"
+	nodeEventRemediationStarted nodeEvent = "RemediationStarted"
+	nodeEventRemediationIssued  nodeEvent = "RemediationIssued"
+	nodeEventRemediationSkipped nodeEvent = "RemediationSkipped"
+	nodeEventRemediationErrored nodeEvent = "RemediationErrored"
+	nodeEventVerifiedRecovered  nodeEvent = "VerifiedRecovered"
+	nodeEventVerifiedFailed     nodeEvent = "VerifiedFailed"
+	nodeEventQuarantined        nodeEvent = "Quarantined"
+	nodeEventReleased           nodeEvent = "Released"
+)
+
+// stateTransition is a change of the state of a node.
+type stateTransition struct {
+	from   nodeState
+	to     nodeState
+	event  nodeEvent
+	reason string
+	at     time.Time
+}
+
+// nodeStatus is the current state of a node and how it got there.
+type nodeStatus struct {
+	state       nodeState
+	since       time.Time
+	transitions []stateTransition
+
+	// checks, checkedAt and decision are the results of the latest evaluation of the node.
+	checks     []checkResult
+	checkedAt  time.Time
+	decision   decision
+	instanceID string
+
+	// approval and approvedAt are the answer to the latest approval request for the node.
+	approval   *Approval
+	approvedAt time.Time
+}
+
+// nextNodeState returns the state a node in the current state moves to when the event happens.
+// It returns the current state when the event does not cause a transition.
+//
+//	Healthy -> Suspect -> Remediating -> Verifying -> Recovered -> Healthy
+//	                                              \-> Failed -> Remediating
+//	Suspect/Failed -> Quarantined -> (released) -> Healthy
+func nextNodeState(current nodeState, event nodeEvent) nodeState {
+	if event == nodeEventQuarantined {
+		return nodeStateQuarantined
+	}
+
+	switch current {
+	case nodeStateHealthy, nodeStateRecovered:
+		switch event {
+		case nodeEventObservedHealthy:
+			return nodeStateHealthy
+		case nodeEventObservedUnhealthy:
+			return nodeStateSuspect
+		case nodeEventRemediationStarted:
+			return nodeStateRemediating
+		}
+	case nodeStateSuspect:
+		switch event {
+		case nodeEventObservedHealthy:
+			return nodeStateHealthy
+		case nodeEventRemediationStarted:
+			return nodeStateRemediating
+		}
+	case nodeStateRemediating:
+		switch event {
+		case nodeEventRemediationIssued:
+			return nodeStateVerifying
+		case nodeEventRemediationSkipped, nodeEventRemediationErrored:
+			return nodeStateSuspect
+		}
+	case nodeStateVerifying:
+		switch event {
+		case nodeEventVerifiedRecovered:
+			return nodeStateRecovered
+		case nodeEventVerifiedFailed:
+			return nodeStateFailed
+		}
+	case nodeStateFailed:
+		switch event {
+		case nodeEventObservedHealthy:
+			return nodeStateHealthy
+		case nodeEventRemediationStarted:
+			return nodeStateRemediating
+		}
+	case nodeStateQuarantined:
+		if event == nodeEventReleased {
+			return nodeStateHealthy
+		}
+	}
+	return current
+}
+
+// transition moves the node to the state the event leads to, and records the transition.
+// Nodes that are seen for the first time start as Healthy. A node that recovered stays Recovered
+// for the rest of the reconcile it recovered in, so that it is visible until the next one.
+func (w *watcher) transition(nodeName string, event nodeEvent, reason string, now time.Time) nodeState {
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+
+	status, ok := w.nodeStatuses[nodeName]
+	if !ok {
+		status = &nodeStatus{
+			state: nodeStateHealthy,
+			since: now,
+		}
+		w.nodeStatuses[nodeName] = status
+		nodesByState.WithLabelValues(w.clusterID, w.nodePoolID, string(status.state)).Inc()
+	}
+
+	next := nextNodeState(status.state, event)
+	if status.state == nodeStateRecovered && next == nodeStateHealthy && !now.After(status.since) {
+		return status.state
+	}
+	if next == status.state {
+		return next
+	}
+
+	slog.Info("Node state changed",
+		"node", nodeName,
+		"from", status.state,
+		"to", next,
+		"event", event,
+		"reason", reason)
+
+	nodesByState.WithLabelValues(w.clusterID, w.nodePoolID, string(status.state)).Dec()
+	nodesByState.WithLabelValues(w.clusterID, w.nodePoolID, string(next)).Inc()
+
+	status.transitions = append(status.transitions, stateTransition{
+		from:   status.state,
+		to:     next,
+		event:  event,
+		reason: reason,
+		at:     now,
+	})
+	if len(status.transitions) > maxStateTransitions {
+		status.transitions = slices.Clone(status.transitions[len(status.transitions)-maxStateTransitions:])
+	}
+	status.state = next
+	status.since = now
+	return next
+}
+
+// recordDecision records the latest evaluation of the node and the decision made on it,
+// and returns whether the decision changed since the previous evaluation.
+func (w *watcher) recordDecision(node *corev1.Node, eval evaluation, d decision, now time.Time) bool {
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+
+	status, ok := w.nodeStatuses[node.GetName()]
+	if !ok {
+		return false
+	}
+	changed := status.decision.action != d.action || status.decision.rule != d.rule
+	status.checks = eval.results
+	status.checkedAt = now
+	status.decision = d
+	if id := instanceIDFromProviderID(node.Spec.ProviderID); id != "" {
+		status.instanceID = id
+	}
+	return changed
+}
+
+// recordApproval records the answer to the latest approval request for the node.
+func (w *watcher) recordApproval(nodeName string, approval Approval, now time.Time) {
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+	if status, ok := w.nodeStatuses[nodeName]; ok {
+		status.approval = &approval
+		status.approvedAt = now
+	}
+}
+
+// recordInstanceID records the Civo instance ID of the node, e.g. as found by a remediation.
+func (w *watcher) recordInstanceID(nodeName, instanceID string) {
+	if instanceID == "" {
+		return
+	}
+
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+	if status, ok := w.nodeStatuses[nodeName]; ok {
+		status.instanceID = instanceID
+	}
+}
+
+// instanceIDFromProviderID returns the Civo instance ID in the provider ID of a node, e.g. "civo://<instance-id>".
+func instanceIDFromProviderID(providerID string) string {
+	id, ok := strings.CutPrefix(providerID, civoProviderIDPrefix)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+// nodeStateOf returns the current state of the node, and false if the node has not been seen yet.
+func (w *watcher) nodeStateOf(nodeName string) (nodeState, bool) {
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+
+	status, ok := w.nodeStatuses[nodeName]
+	if !ok {
+		return "", false
+	}
+	return status.state, true
+}
+
+// forgetNodes drops the state, reboot history and last remediation time of nodes that no longer exist,
+// unless their remediation is still being verified, and when they were first seen unhealthy.
+func (w *watcher) forgetNodes(existing map[string]bool) {
+	w.unhealthySince.Range(func(key, _ any) bool {
+		if nodeName, _ := key.(string); !existing[nodeName] {
+			w.unhealthySince.Delete(key)
+		}
+		return true
+	})
+
+	w.verificationsMu.Lock()
+	verifying := make(map[string]bool, len(w.verifications))
+	for nodeName := range w.verifications {
+		verifying[nodeName] = true
+	}
+	w.verificationsMu.Unlock()
+
+	w.lastRebootCmdTimes.Range(func(key, _ any) bool {
+		if nodeName, _ := key.(string); !existing[nodeName] && !verifying[nodeName] {
+			w.lastRebootCmdTimes.Delete(key)
+		}
+		return true
+	})
+
+	w.rebootHistoryMu.Lock()
+	for nodeName := range w.rebootHistory {
+		if !existing[nodeName] && !verifying[nodeName] {
+			delete(w.rebootHistory, nodeName)
+		}
+	}
+	w.rebootHistoryMu.Unlock()
+
+	w.nodeStatusesMu.Lock()
+	defer w.nodeStatusesMu.Unlock()
+
+	for nodeName, status := range w.nodeStatuses {
+		if existing[nodeName] || verifying[nodeName] {
+			continue
+		}
+		nodesByState.WithLabelValues(w.clusterID, w.nodePoolID, string(status.state)).Dec()
+		delete(w.nodeStatuses, nodeName)
+	}
+}
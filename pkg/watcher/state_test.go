@@ -0,0 +1,212 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNextNodeState(t *testing.T) {
+	type test struct {
+		name    string
+		current nodeState
+		event   nodeEvent
+		want    nodeState
+	}
+
+	tests := []test{
+		{
+			name:    "Healthy node becomes Suspect when observed unhealthy",
+			current: nodeStateHealthy,
+			event:   nodeEventObservedUnhealthy,
+			want:    nodeStateSuspect,
+		},
+		{
+			name:    "Suspect node becomes Healthy when observed healthy",
+			current: nodeStateSuspect,
+			event:   nodeEventObservedHealthy,
+			want:    nodeStateHealthy,
+		},
+		{
+			name:    "Suspect node stays Suspect when observed unhealthy",
+			current: nodeStateSuspect,
+			event:   nodeEventObservedUnhealthy,
+			want:    nodeStateSuspect,
+		},
+		{
+			name:    "Suspect node becomes Remediating when remediation starts",
+			current: nodeStateSuspect,
+			event:   nodeEventRemediationStarted,
+			want:    nodeStateRemediating,
+		},
+		{
+			name:    "Remediating node becomes Verifying when remediation is issued",
+			current: nodeStateRemediating,
+			event:   nodeEventRemediationIssued,
+			want:    nodeStateVerifying,
+		},
+		{
+			name:    "Remediating node goes back to Suspect when remediation is skipped",
+			current: nodeStateRemediating,
+			event:   nodeEventRemediationSkipped,
+			want:    nodeStateSuspect,
+		},
+		{
+			name:    "Remediating node goes back to Suspect when remediation errors",
+			current: nodeStateRemediating,
+			event:   nodeEventRemediationErrored,
+			want:    nodeStateSuspect,
+		},
+		{
+			name:    "Verifying node stays Verifying when observed healthy",
+			current: nodeStateVerifying,
+			event:   nodeEventObservedHealthy,
+			want:    nodeStateVerifying,
+		},
+		{
+			name:    "Verifying node becomes Recovered when verified recovered",
+			current: nodeStateVerifying,
+			event:   nodeEventVerifiedRecovered,
+			want:    nodeStateRecovered,
+		},
+		{
+			name:    "Verifying node becomes Failed when verified failed",
+			current: nodeStateVerifying,
+			event:   nodeEventVerifiedFailed,
+			want:    nodeStateFailed,
+		},
+		{
+			name:    "Recovered node becomes Healthy when observed healthy",
+			current: nodeStateRecovered,
+			event:   nodeEventObservedHealthy,
+			want:    nodeStateHealthy,
+		},
+		{
+			name:    "Failed node stays Failed when observed unhealthy",
+			current: nodeStateFailed,
+			event:   nodeEventObservedUnhealthy,
+			want:    nodeStateFailed,
+		},
+		{
+			name:    "Failed node becomes Remediating when remediation starts again",
+			current: nodeStateFailed,
+			event:   nodeEventRemediationStarted,
+			want:    nodeStateRemediating,
+		},
+		{
+			name:    "Suspect node becomes Quarantined when quarantined",
+			current: nodeStateSuspect,
+			event:   nodeEventQuarantined,
+			want:    nodeStateQuarantined,
+		},
+		{
+			name:    "Quarantined node stays Quarantined when observed healthy",
+			current: nodeStateQuarantined,
+			event:   nodeEventObservedHealthy,
+			want:    nodeStateQuarantined,
+		},
+		{
+			name:    "Quarantined node becomes Healthy when released",
+			current: nodeStateQuarantined,
+			event:   nodeEventReleased,
+			want:    nodeStateHealthy,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := nextNodeState(test.current, test.event)
+			if got != test.want {
+				t.Errorf("got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTransition(t *testing.T) {
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(&FakeClient{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := w.(*watcher)
+
+	now := time.Now()
+	events := []nodeEvent{
+		nodeEventObservedUnhealthy,
+		nodeEventObservedUnhealthy,
+		nodeEventRemediationStarted,
+		nodeEventRemediationIssued,
+		nodeEventVerifiedRecovered,
+		nodeEventObservedHealthy,
+	}
+	for i, event := range events {
+		obj.transition("node-01", event, "test", now.Add(time.Duration(i)*time.Minute))
+	}
+
+	status := obj.nodeStatuses["node-01"]
+	if status.state != nodeStateHealthy {
+		t.Errorf("state = %v, want %v", status.state, nodeStateHealthy)
+	}
+	if !status.since.Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("since = %v, want %v", status.since, now.Add(5*time.Minute))
+	}
+
+	want := []nodeState{nodeStateSuspect, nodeStateRemediating, nodeStateVerifying, nodeStateRecovered, nodeStateHealthy}
+	if len(status.transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %d transitions", status.transitions, len(want))
+	}
+	for i, transition := range status.transitions {
+		if transition.to != want[i] {
+			t.Errorf("transitions[%d].to = %v, want %v", i, transition.to, want[i])
+		}
+	}
+
+	for i := range maxStateTransitions {
+		obj.transition("node-01", nodeEventObservedUnhealthy, "test", now.Add(time.Duration(i)*time.Hour))
+		obj.transition("node-01", nodeEventObservedHealthy, "test", now.Add(time.Duration(i)*time.Hour))
+	}
+	if got := len(obj.nodeStatuses["node-01"].transitions); got != maxStateTransitions {
+		t.Errorf("transitions = %d, want %d", got, maxStateTransitions)
+	}
+
+	for _, event := range events[:5] {
+		obj.transition("node-02", event, "test", now)
+	}
+	if got := obj.transition("node-02", nodeEventObservedHealthy, "test", now); got != nodeStateRecovered {
+		t.Errorf("state observed in the reconcile the node recovered in = %v, want %v", got, nodeStateRecovered)
+	}
+	if got := obj.transition("node-02", nodeEventObservedHealthy, "test", now.Add(time.Minute)); got != nodeStateHealthy {
+		t.Errorf("state observed in the next reconcile = %v, want %v", got, nodeStateHealthy)
+	}
+
+	obj.unhealthySince.Store("node-01", now)
+	obj.unhealthySince.Store("node-02", now)
+	for _, nodeName := range []string{"node-01", "node-02", "node-03"} {
+		obj.recordReboot(nodeName, now)
+		obj.lastRebootCmdTimes.Store(nodeName, now)
+	}
+	obj.startVerification("node-03", RemediationResult{InstanceID: "instance-03"}, now)
+	obj.forgetNodes(map[string]bool{"node-02": true})
+	if _, ok := obj.nodeStateOf("node-01"); ok {
+		t.Errorf("state of node that no longer exists was not forgotten")
+	}
+	if _, ok := obj.unhealthySince.Load("node-01"); ok {
+		t.Errorf("unhealthy since of node that no longer exists was not forgotten")
+	}
+	if _, ok := obj.unhealthySince.Load("node-02"); !ok {
+		t.Errorf("unhealthy since of existing node was forgotten")
+	}
+	for nodeName, want := range map[string]bool{"node-01": false, "node-02": true, "node-03": true} {
+		if _, got := obj.lastRebootCmdTimes.Load(nodeName); got != want {
+			t.Errorf("last remediation time of %s kept = %v, want %v", nodeName, got, want)
+		}
+		if got := len(obj.rebootHistoryOf(nodeName)) > 0; got != want {
+			t.Errorf("reboot history of %s kept = %v, want %v", nodeName, got, want)
+		}
+	}
+}
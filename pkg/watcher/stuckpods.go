@@ -0,0 +1,95 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// mirrorPodAnnotationKey is the annotation of the mirror pods of static pods, which cannot be deleted through the API.
+const mirrorPodAnnotationKey = "kubernetes.io/config.mirror"
+
+// stuckPodPolicy selects the pods that are force deleted from a node after it has been remediated.
+type stuckPodPolicy struct {
+	// namespaces limits the policy to pods in these namespaces. All namespaces are matched when it is empty.
+	namespaces []string
+	selector   labels.Selector
+}
+
+// newStuckPodPolicy returns the policy for the namespaces and the label selector.
+func newStuckPodPolicy(namespaces []string, selector string) (*stuckPodPolicy, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stuck pod selector %q: %w", selector, err)
+	}
+	return &stuckPodPolicy{
+		namespaces: namespaces,
+		selector:   s,
+	}, nil
+}
+
+// matches checks if the pod is selected by the policy.
+func (p *stuckPodPolicy) matches(pod *corev1.Pod) bool {
+	if len(p.namespaces) > 0 && !slices.Contains(p.namespaces, pod.GetNamespace()) {
+		return false
+	}
+	return p.selector.Matches(labels.Set(pod.GetLabels()))
+}
+
+// isPodStuck checks if the pod is stuck terminating past its deletion deadline, or in the Unknown phase,
+// which is what happens to pods whose node went away without the kubelet cleaning them up.
+func isPodStuck(pod *corev1.Pod, now time.Time) bool {
+	if _, ok := pod.GetAnnotations()[mirrorPodAnnotationKey]; ok {
+		return false
+	}
+	if pod.DeletionTimestamp != nil && now.After(pod.DeletionTimestamp.Time) {
+		return true
+	}
+	return pod.Status.Phase == corev1.PodUnknown
+}
+
+// deleteStuckPods force deletes the pods on the node that are stuck and match the stuck pod policy,
+// so that their controllers can reschedule them.
+func (w *watcher) deleteStuckPods(ctx context.Context, nodeName string, now time.Time) {
+	if w.stuckPodPolicy == nil {
+		return
+	}
+
+	pods, err := w.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		slog.Error("Failed to list pods of remediated Node", "node", nodeName, "error", err)
+		return
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != nodeName || !isPodStuck(&pod, now) || !w.stuckPodPolicy.matches(&pod) {
+			continue
+		}
+		err := w.client.CoreV1().Pods(pod.GetNamespace()).Delete(ctx, pod.GetName(), metav1.DeleteOptions{
+			GracePeriodSeconds: new(int64),
+		})
+		if err != nil {
+			slog.Error("Failed to force delete stuck pod",
+				"node", nodeName,
+				"namespace", pod.GetNamespace(),
+				"pod", pod.GetName(),
+				"error", err)
+			continue
+		}
+		slog.Info("Stuck pod is force deleted",
+			"node", nodeName,
+			"namespace", pod.GetNamespace(),
+			"pod", pod.GetName(),
+			"phase", pod.Status.Phase,
+			"deletionTimestamp", pod.DeletionTimestamp)
+	}
+}
@@ -0,0 +1,152 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeleteStuckPods(t *testing.T) {
+	now := time.Now()
+
+	newPod := func(name, namespace, nodeName string, mutate func(*corev1.Pod)) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					"app": "gpu-job",
+				},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		}
+		if mutate != nil {
+			mutate(pod)
+		}
+		return pod
+	}
+	terminating := func(pod *corev1.Pod) {
+		pod.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Minute)}
+	}
+	unknown := func(pod *corev1.Pod) {
+		pod.Status.Phase = corev1.PodUnknown
+	}
+
+	type test struct {
+		name        string
+		opts        []Option
+		pods        []*corev1.Pod
+		wantDeleted []string
+	}
+
+	tests := []test{
+		{
+			name: "Deletes pods stuck terminating or unknown on the node",
+			opts: []Option{WithStuckPodCleanup("true")},
+			pods: []*corev1.Pod{
+				newPod("terminating", "default", "node-01", terminating),
+				newPod("unknown", "default", "node-01", unknown),
+				newPod("running", "default", "node-01", nil),
+				newPod("other-node", "default", "node-02", terminating),
+				newPod("not-yet-due", "default", "node-01", func(pod *corev1.Pod) {
+					pod.DeletionTimestamp = &metav1.Time{Time: now.Add(time.Minute)}
+				}),
+				newPod("mirror", "default", "node-01", func(pod *corev1.Pod) {
+					unknown(pod)
+					pod.Annotations = map[string]string{mirrorPodAnnotationKey: "hash"}
+				}),
+			},
+			wantDeleted: []string{"terminating", "unknown"},
+		},
+		{
+			name: "Deletes only pods in the configured namespaces",
+			opts: []Option{WithStuckPodCleanup("true"), WithStuckPodNamespaces("jobs")},
+			pods: []*corev1.Pod{
+				newPod("in-jobs", "jobs", "node-01", terminating),
+				newPod("in-default", "default", "node-01", terminating),
+			},
+			wantDeleted: []string{"in-jobs"},
+		},
+		{
+			name: "Deletes only pods in the namespaces of the option applied last",
+			opts: []Option{WithStuckPodCleanup("true"), WithStuckPodNamespaces("default"), WithStuckPodNamespaces("jobs")},
+			pods: []*corev1.Pod{
+				newPod("in-jobs", "jobs", "node-01", terminating),
+				newPod("in-default", "default", "node-01", terminating),
+			},
+			wantDeleted: []string{"in-jobs"},
+		},
+		{
+			name: "Deletes only pods matching the configured selector",
+			opts: []Option{WithStuckPodCleanup("true"), WithStuckPodSelector("app=gpu-job")},
+			pods: []*corev1.Pod{
+				newPod("matching", "default", "node-01", terminating),
+				newPod("not-matching", "default", "node-01", func(pod *corev1.Pod) {
+					terminating(pod)
+					pod.Labels = map[string]string{"app": "web"}
+				}),
+			},
+			wantDeleted: []string{"matching"},
+		},
+		{
+			name: "Deletes nothing when cleanup is disabled",
+			pods: []*corev1.Pod{
+				newPod("terminating", "default", "node-01", terminating),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, pod := range test.pods {
+				if _, err := client.CoreV1().Pods(pod.Namespace).Create(t.Context(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				append([]Option{
+					WithKubernetesClient(client),
+					WithCivoClient(&FakeClient{}),
+				}, test.opts...)...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w.(*watcher).deleteStuckPods(t.Context(), "node-01", now)
+
+			remaining := make(map[string]bool)
+			pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(t.Context(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, pod := range pods.Items {
+				remaining[pod.Name] = true
+			}
+			if got, want := len(test.pods)-len(pods.Items), len(test.wantDeleted); got != want {
+				t.Errorf("deleted = %d, want %d", got, want)
+			}
+			for _, name := range test.wantDeleted {
+				if remaining[name] {
+					t.Errorf("pod %s was not deleted", name)
+				}
+			}
+		})
+	}
+}
+
+func TestNewStuckPodPolicy(t *testing.T) {
+	if _, err := newStuckPodPolicy(nil, "app in (a"); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+}
@@ -0,0 +1,222 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Outcomes of a remediation.
+const (
+	outcomeRecovered       = "recovered"
+	outcomeStillUnhealthy  = "still_unhealthy"
+	outcomeInstanceMissing = "instance_gone"
+)
+
+// Reasons of the events node-agent records on nodes.
+const (
+	eventReasonRemediating          = "NodeAgentRemediating"
+	eventReasonRemediationSucceeded = "NodeAgentRemediationSucceeded"
+	eventReasonRemediationFailed    = "NodeAgentRemediationFailed"
+)
+
+// verification is a remediation whose outcome is not known yet.
+type verification struct {
+	instanceID  string
+	remediateAt time.Time
+	deadline    time.Time
+	// stuckPodsDeleted is true once the stuck pods of the node have been deleted.
+	stuckPodsDeleted bool
+	// awaitRestart is true when the node only counts as recovered once it went down after the remediation,
+	// e.g. rebooted, as it is usually still Ready right after the remediation was issued.
+	awaitRestart bool
+	// sawNotReady is true once the node was seen not ready after the remediation.
+	sawNotReady bool
+	// replaced is true when the instance of the node was replaced, so that the node going away is its recovery.
+	replaced bool
+}
+
+// startVerification starts tracking the remediation of the node until it recovers or the deadline passes.
+// Unless the remediation was lightweight, the node only recovers once it went down after the remediation.
+func (w *watcher) startVerification(nodeName string, result RemediationResult, now time.Time) {
+	timeout := w.verificationTimeout
+	if timeout <= 0 {
+		timeout = w.rebootTimeWindowMinutes * time.Minute
+	}
+
+	w.verificationsMu.Lock()
+	defer w.verificationsMu.Unlock()
+	w.verifications[nodeName] = &verification{
+		instanceID:   result.InstanceID,
+		remediateAt:  now,
+		deadline:     now.Add(timeout),
+		awaitRestart: !result.Lightweight,
+		replaced:     result.Replaced,
+	}
+}
+
+// verifyRemediations checks the outcome of every remediation that is being tracked against the health checks of the current nodes,
+// and records the outcome of those that recovered, went away or passed their deadline.
+// The verifications are checked on a copy, so that the lock is not held during the API calls.
+func (w *watcher) verifyRemediations(ctx context.Context, nodes []corev1.Node, now time.Time) {
+	w.verificationsMu.Lock()
+	pending := make(map[string]verification, len(w.verifications))
+	for nodeName, v := range w.verifications {
+		pending[nodeName] = *v
+	}
+	w.verificationsMu.Unlock()
+
+	for nodeName, v := range pending {
+		idx := -1
+		for i := range nodes {
+			if nodes[i].GetName() == nodeName {
+				idx = i
+				break
+			}
+		}
+
+		if idx >= 0 && !isNodeReady(&nodes[idx]) && !v.sawNotReady {
+			v.sawNotReady = true
+			w.updateVerification(nodeName, v.remediateAt, func(v *verification) {
+				v.sawNotReady = true
+			})
+		}
+
+		var outcome string
+		switch {
+		case idx >= 0 && v.restarted(&nodes[idx]) && !w.evaluateNode(ctx, &nodes[idx]).needsRemediation():
+			outcome = outcomeRecovered
+		case idx < 0 && v.replaced && !w.instanceExists(ctx, nodeName):
+			outcome = outcomeRecovered
+		case idx < 0 && !w.instanceExists(ctx, nodeName):
+			outcome = outcomeInstanceMissing
+		case now.After(v.deadline):
+			outcome = outcomeStillUnhealthy
+		default:
+			// Pods only get stuck once the node went down, so they are deleted when it is back Ready.
+			if idx >= 0 && isNodeReady(&nodes[idx]) && v.restarted(&nodes[idx]) && !v.stuckPodsDeleted {
+				w.deleteStuckPods(ctx, nodeName, now)
+				w.updateVerification(nodeName, v.remediateAt, func(v *verification) {
+					v.stuckPodsDeleted = true
+				})
+			}
+			slog.Info("Waiting for remediated Node to recover",
+				"node", nodeName,
+				"instanceID", v.instanceID,
+				"deadline", v.deadline.String())
+			continue
+		}
+
+		if !w.finishVerification(nodeName, v.remediateAt) {
+			// The node was remediated again while its previous remediation was being checked.
+			continue
+		}
+		w.recordOutcome(nodeName, &v, outcome, now)
+		if outcome != outcomeRecovered {
+			w.notify(ctx, Notification{
+				Event:      NotificationFailed,
+				Node:       nodeName,
+				InstanceID: v.instanceID,
+				Action:     w.remediator.Name(),
+				Reason:     fmt.Sprintf("node did not recover after remediation: %s", outcome),
+				Attempt:    len(w.rebootHistoryOf(nodeName)),
+				Time:       now,
+			})
+		}
+		if idx >= 0 && !v.stuckPodsDeleted {
+			w.deleteStuckPods(ctx, nodeName, now)
+		}
+	}
+}
+
+// restarted checks if the node went down since the remediation, when it has to. It did when it was seen
+// not ready, or when its Ready condition changed after the remediation.
+func (v *verification) restarted(node *corev1.Node) bool {
+	if !v.awaitRestart || v.sawNotReady {
+		return true
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.LastTransitionTime.After(v.remediateAt)
+		}
+	}
+	return false
+}
+
+// updateVerification updates the verification of the node, unless the node was remediated again since remediateAt.
+func (w *watcher) updateVerification(nodeName string, remediateAt time.Time, update func(*verification)) {
+	w.verificationsMu.Lock()
+	defer w.verificationsMu.Unlock()
+
+	if v, ok := w.verifications[nodeName]; ok && v.remediateAt.Equal(remediateAt) {
+		update(v)
+	}
+}
+
+// finishVerification stops tracking the remediation of the node at remediateAt.
+// It returns false when the node was remediated again since, and the newer remediation is still being tracked.
+func (w *watcher) finishVerification(nodeName string, remediateAt time.Time) bool {
+	w.verificationsMu.Lock()
+	defer w.verificationsMu.Unlock()
+
+	v, ok := w.verifications[nodeName]
+	if !ok || !v.remediateAt.Equal(remediateAt) {
+		return false
+	}
+	delete(w.verifications, nodeName)
+	return true
+}
+
+// instanceExists checks if the Civo instance of the node can still be found.
+func (w *watcher) instanceExists(ctx context.Context, nodeName string) bool {
+	_, err := callCivo(ctx, func() (*civogo.Instance, error) {
+		return w.civoClient.FindKubernetesClusterInstance(w.clusterID, nodeName)
+	})
+	if err != nil {
+		slog.Info("Instance of remediated Node not found", "node", nodeName, "error", err)
+		return false
+	}
+	return true
+}
+
+func (w *watcher) recordOutcome(nodeName string, v *verification, outcome string, now time.Time) {
+	elapsed := now.Sub(v.remediateAt)
+	remediationOutcomesTotal.WithLabelValues(w.clusterID, w.nodePoolID, outcome).Inc()
+
+	if outcome == outcomeRecovered {
+		w.transition(nodeName, nodeEventVerifiedRecovered, outcome, now)
+		remediationRecoverySeconds.WithLabelValues(w.clusterID, w.nodePoolID).Observe(elapsed.Seconds())
+		slog.Info("Remediation succeeded, Node has recovered",
+			"node", nodeName,
+			"instanceID", v.instanceID,
+			"outcome", outcome,
+			"elapsed", elapsed.String())
+		w.eventRecorder.Eventf(nodeReference(nodeName), corev1.EventTypeNormal, eventReasonRemediationSucceeded,
+			"Node recovered %s after remediation of instance %s", elapsed.Round(time.Second), v.instanceID)
+		return
+	}
+
+	w.transition(nodeName, nodeEventVerifiedFailed, outcome, now)
+	slog.Error("Remediation failed",
+		"node", nodeName,
+		"instanceID", v.instanceID,
+		"outcome", outcome,
+		"elapsed", elapsed.String())
+	w.eventRecorder.Eventf(nodeReference(nodeName), corev1.EventTypeWarning, eventReasonRemediationFailed,
+		"Remediation of instance %s failed after %s: %s", v.instanceID, elapsed.Round(time.Second), outcome)
+}
+
+// nodeReference returns the reference events about the node are recorded on.
+// Like the kubelet, the node name is used as the UID so that events are shown by kubectl describe node.
+func nodeReference(nodeName string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind: "Node",
+		Name: nodeName,
+		UID:  types.UID(nodeName),
+	}
+}
@@ -0,0 +1,312 @@
+package watcher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestVerifyRemediations(t *testing.T) {
+	now := time.Now()
+
+	newNode := func(ready corev1.ConditionStatus, readySince time.Time, gpus string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-01",
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             ready,
+						LastTransitionTime: metav1.NewTime(readySince),
+					},
+				},
+				Allocatable: corev1.ResourceList{
+					gpuResourceName: resource.MustParse(gpus),
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name             string
+		nodes            []corev1.Node
+		sawNotReady      bool
+		replaced         bool
+		deadline         time.Time
+		instanceErr      error
+		wantPending      bool
+		wantEventReason  string
+		wantEventOutcome string
+	}
+
+	tests := []test{
+		{
+			name:            "Records recovered when node is ready with the desired GPU count after restarting",
+			nodes:           []corev1.Node{newNode(corev1.ConditionTrue, now.Add(-5*time.Minute), "8")},
+			deadline:        now.Add(time.Hour),
+			wantEventReason: eventReasonRemediationSucceeded,
+		},
+		{
+			name:            "Records recovered when node was seen not ready since the remediation",
+			nodes:           []corev1.Node{newNode(corev1.ConditionTrue, now.Add(-time.Hour), "8")},
+			sawNotReady:     true,
+			deadline:        now.Add(time.Hour),
+			wantEventReason: eventReasonRemediationSucceeded,
+		},
+		{
+			name:        "Keeps waiting when node has not gone down since the remediation",
+			nodes:       []corev1.Node{newNode(corev1.ConditionTrue, now.Add(-time.Hour), "8")},
+			deadline:    now.Add(time.Hour),
+			wantPending: true,
+		},
+		{
+			name:        "Keeps waiting when node is not ready and the deadline has not passed",
+			nodes:       []corev1.Node{newNode(corev1.ConditionFalse, now.Add(-5*time.Minute), "8")},
+			deadline:    now.Add(time.Hour),
+			wantPending: true,
+		},
+		{
+			name:             "Records still unhealthy when node lacks GPUs after the deadline",
+			nodes:            []corev1.Node{newNode(corev1.ConditionTrue, now.Add(-5*time.Minute), "7")},
+			deadline:         now.Add(-time.Minute),
+			wantEventReason:  eventReasonRemediationFailed,
+			wantEventOutcome: outcomeStillUnhealthy,
+		},
+		{
+			name:             "Records instance gone when node and instance no longer exist",
+			deadline:         now.Add(time.Hour),
+			instanceErr:      errors.New("zero matches"),
+			wantEventReason:  eventReasonRemediationFailed,
+			wantEventOutcome: outcomeInstanceMissing,
+		},
+		{
+			name:            "Records recovered when a replaced node and its instance no longer exist",
+			replaced:        true,
+			deadline:        now.Add(time.Hour),
+			instanceErr:     errors.New("zero matches"),
+			wantEventReason: eventReasonRemediationSucceeded,
+		},
+		{
+			name:        "Keeps waiting when node no longer exists but the instance does",
+			deadline:    now.Add(time.Hour),
+			wantPending: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			civoClient := &FakeClient{
+				FindKubernetesClusterInstanceFunc: func(clusterID, search string) (*civogo.Instance, error) {
+					if test.instanceErr != nil {
+						return nil, test.instanceErr
+					}
+					return &civogo.Instance{ID: "instance-01"}, nil
+				},
+			}
+			recorder := record.NewFakeRecorder(10)
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(civoClient),
+				WithDesiredGPUCount(testNodeDesiredGPUCount),
+				WithEventRecorder(recorder),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := w.(*watcher)
+			obj.verifications["node-01"] = &verification{
+				instanceID:   "instance-01",
+				remediateAt:  now.Add(-10 * time.Minute),
+				deadline:     test.deadline,
+				awaitRestart: true,
+				sawNotReady:  test.sawNotReady,
+				replaced:     test.replaced,
+			}
+
+			obj.verifyRemediations(t.Context(), test.nodes, now)
+
+			if _, pending := obj.verifications["node-01"]; pending != test.wantPending {
+				t.Errorf("pending = %v, want %v", pending, test.wantPending)
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if test.wantEventReason == "" {
+					t.Errorf("unexpected event: %s", event)
+				}
+				if !strings.Contains(event, test.wantEventReason) {
+					t.Errorf("event = %q, want reason %s", event, test.wantEventReason)
+				}
+				if !strings.Contains(event, test.wantEventOutcome) {
+					t.Errorf("event = %q, want outcome %s", event, test.wantEventOutcome)
+				}
+			default:
+				if test.wantEventReason != "" {
+					t.Errorf("no event recorded, want reason %s", test.wantEventReason)
+				}
+			}
+		})
+	}
+}
+
+func TestRemediateNodeStartsVerification(t *testing.T) {
+	civoClient := &FakeClient{
+		FindKubernetesClusterInstanceFunc: func(clusterID, search string) (*civogo.Instance, error) {
+			return &civogo.Instance{ID: "instance-01", Status: "ACTIVE"}, nil
+		},
+		HardRebootInstanceFunc: func(id string) (*civogo.SimpleResponse, error) {
+			return new(civogo.SimpleResponse), nil
+		},
+	}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(civoClient),
+		WithEventRecorder(record.NewFakeRecorder(10)),
+		WithVerificationTimeoutMinutes("15"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := w.(*watcher)
+	if _, err := obj.remediateNode(t.Context(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-01"}}, "node is not ready"); err != nil {
+		t.Fatal(err)
+	}
+
+	v, ok := obj.verifications["node-01"]
+	if !ok {
+		t.Fatal("verification was not started")
+	}
+	if v.instanceID != "instance-01" {
+		t.Errorf("instanceID = %s, want %s", v.instanceID, "instance-01")
+	}
+	if got := v.deadline.Sub(v.remediateAt); got != 15*time.Minute {
+		t.Errorf("timeout = %v, want %v", got, 15*time.Minute)
+	}
+	if !v.awaitRestart {
+		t.Error("verification of a reboot does not wait for the node to restart")
+	}
+}
+
+func TestVerifyRemediationsDoesNotHoldLockDuringAPICalls(t *testing.T) {
+	var obj *watcher
+	civoClient := &FakeClient{
+		FindKubernetesClusterInstanceFunc: func(clusterID, search string) (*civogo.Instance, error) {
+			if !obj.verificationsMu.TryLock() {
+				t.Error("verifications are locked during the Civo API call")
+				return nil, errors.New("locked")
+			}
+			obj.verificationsMu.Unlock()
+			// A remediation that starts meanwhile is kept.
+			obj.startVerification(search, RemediationResult{InstanceID: "instance-02"}, time.Now())
+			return nil, errors.New("zero matches")
+		},
+	}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(civoClient),
+		WithEventRecorder(record.NewFakeRecorder(10)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj = w.(*watcher)
+	obj.verifications["node-01"] = &verification{
+		instanceID:  "instance-01",
+		remediateAt: time.Now().Add(-10 * time.Minute),
+		deadline:    time.Now().Add(time.Hour),
+	}
+
+	obj.verifyRemediations(t.Context(), nil, time.Now())
+
+	v, ok := obj.verifications["node-01"]
+	if !ok || v.instanceID != "instance-02" {
+		t.Errorf("verification = %+v, want the remediation that started during the check", v)
+	}
+}
+
+func TestVerifyRemediationsDeletesStuckPodsAfterRestart(t *testing.T) {
+	remediateAt := time.Now()
+
+	newNode := func(readySince time.Time) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-01",
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(readySince),
+					},
+				},
+				Allocatable: corev1.ResourceList{
+					gpuResourceName: resource.MustParse("8"),
+				},
+			},
+		}
+	}
+
+	client := fake.NewSimpleClientset()
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(client),
+		WithCivoClient(&FakeClient{}),
+		WithDesiredGPUCount(testNodeDesiredGPUCount),
+		WithEventRecorder(record.NewFakeRecorder(10)),
+		WithStuckPodCleanup("true"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := w.(*watcher)
+	obj.startVerification("node-01", RemediationResult{InstanceID: "instance-01"}, remediateAt)
+
+	// The node is still Ready from before the reboot.
+	obj.verifyRemediations(t.Context(), []corev1.Node{newNode(remediateAt.Add(-time.Hour))}, remediateAt.Add(10*time.Second))
+	if v := obj.verifications["node-01"]; v == nil || v.stuckPodsDeleted {
+		t.Fatalf("verification = %+v, want pending without stuck pods deleted", v)
+	}
+
+	// The pods of the node get stuck while it is down.
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stuck",
+			Namespace: "default",
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-01",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodUnknown,
+		},
+	}
+	if _, err := client.CoreV1().Pods(pod.Namespace).Create(t.Context(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The node is back Ready after the reboot.
+	obj.verifyRemediations(t.Context(), []corev1.Node{newNode(remediateAt.Add(time.Minute))}, remediateAt.Add(2*time.Minute))
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(t.Context(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("pods = %d, want the stuck pod deleted after the node restarted", len(pods.Items))
+	}
+}
@@ -1,19 +1,28 @@
 package watcher
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/civo/civogo"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 )
 
 // Version is the current version of the this watcher
@@ -22,10 +31,45 @@ var Version string = "0.0.1"
 const (
 	nodePoolLabelKey = "kubernetes.civo.com/civo-node-pool"
 	gpuResourceName  = "nvidia.com/gpu"
+
+	// disabledKey is the label or annotation that excludes a node from remediation when set to "true".
+	disabledKey = "node-agent.civo.com/disabled"
+	// enabledKey is the label or annotation that includes a node in remediation when set to "true" in opt-in mode.
+	enabledKey = "node-agent.civo.com/enabled"
+	// cordonedByAgentKey is the annotation node-agent sets on nodes it cordons itself,
+	// so that those cordons are not mistaken for an operator working on the node.
+	cordonedByAgentKey = "node-agent.civo.com/cordoned"
+)
+
+// clusterStatusActive is the Civo status of a Kubernetes cluster that is not being built, upgraded or scaled.
+const clusterStatusActive = "ACTIVE"
+
+// Civo instance statuses that affect how an unhealthy node is handled.
+const (
+	instanceStatusActive        = "ACTIVE"
+	instanceStatusBuildPending  = "BUILD_PENDING"
+	instanceStatusBuilding      = "BUILDING"
+	instanceStatusRebooting     = "REBOOTING"
+	instanceStatusHardRebooting = "HARD_REBOOTING"
+	instanceStatusStarting      = "STARTING"
+	instanceStatusStopping      = "STOPPING"
+	instanceStatusShuttingDown  = "SHUTTING_DOWN"
+	instanceStatusStopped       = "STOPPED"
+	instanceStatusShutoff       = "SHUTOFF"
+	instanceStatusDeleting      = "DELETING"
+	instanceStatusDeleted       = "DELETED"
 )
 
 type Watcher interface {
 	Run(ctx context.Context) error
+	// Check evaluates every node in the pool once and reports what would be done with it, without remediating any node.
+	Check(ctx context.Context) (*Report, error)
+	// Explain evaluates the node once and explains what would be done with it, without remediating it.
+	Explain(ctx context.Context, nodeName string) (*Explanation, error)
+	// PlanReboot checks if the node can be rebooted safely by an operator.
+	PlanReboot(ctx context.Context, nodeName string) (*RebootPlan, error)
+	// Reboot remediates the node regardless of its health.
+	Reboot(ctx context.Context, nodeName string) error
 }
 
 type watcher struct {
@@ -34,24 +78,111 @@ type watcher struct {
 	clientCfgPath string
 
 	clusterID               string
+	nodePoolID              string
 	region                  string
 	apiKey                  string
 	apiURL                  string
 	nodeDesiredGPUCount     int
 	rebootTimeWindowMinutes time.Duration
+	nodeStartupGracePeriod  time.Duration
+	optIn                   bool
+	skipCordonedNodes       bool
+	maintenanceTaintKeys    []string
+	httpAddress             string
+	reconcileInterval       time.Duration
+	reconcileJitter         time.Duration
+	reconcileTimeout        time.Duration
+
+	rebootWindows        string
+	rebootBlackouts      string
+	rebootWindowTimezone string
+	rebootWindowOverride time.Duration
+	maintenance          *maintenancePolicy
+
+	maxRebootsPerNode int
+	maxRebootsPeriod  time.Duration
+
+	verificationTimeout time.Duration
+	eventRecorder       record.EventRecorder
+	// eventBroadcaster is set when the event recorder was created by setupEventRecorder.
+	// pendingEvents are the events it has not written yet.
+	eventBroadcaster record.EventBroadcaster
+	pendingEvents    sync.WaitGroup
+	stopEventsOnce   sync.Once
+
+	remediationName string
+	remediator      Remediator
+
+	// healthChecks are the checks every node is evaluated with.
+	// The built-in GPU count and Ready checks are always evaluated first.
+	healthChecks []HealthCheck
+	// devicePluginNamespace enables the device plugin check and remediation when set.
+	devicePluginNamespace     string
+	devicePluginSelector      string
+	devicePluginRestartPeriod time.Duration
+
+	// notifier is sent notifications about unhealthy nodes and their remediations, if set.
+	// Unless one has been set with an Option, a webhook notifier is created when webhookURL is set.
+	notifier        Notifier
+	webhookURL      string
+	webhookTemplate string
+	webhookEvents   string
+	webhookRetries  int
+	notifications   sync.WaitGroup
+
+	// approver approves every remediation before it is taken, if set. approvalDefault is the
+	// approval that applies when the approver cannot give an answer.
+	// Unless one has been set with an Option, a webhook approver is created when approvalURL is set.
+	approver        Approver
+	approvalURL     string
+	approvalTimeout time.Duration
+	approvalDefault bool
+
+	// stuckPodCleanup enables force deleting the stuck pods selected by stuckPodPolicy from remediated nodes.
+	stuckPodCleanup    bool
+	stuckPodNamespaces []string
+	stuckPodSelector   string
+	stuckPodPolicy     *stuckPodPolicy
+
+	// unhealthyRules is the raw JSON array of custom CEL rules, compiled into health checks by NewWatcher.
+	unhealthyRules string
 
 	// NOTE: This is only effective when running with a single node-agent. If we want to run multiple instances, additional logic modifications will be required.
 	lastRebootCmdTimes sync.Map
 
+	// unhealthySince holds the time each node was first seen unhealthy by this node-agent.
+	unhealthySince sync.Map
+
+	// rebootHistory holds the times each node was rebooted within the max reboots period.
+	rebootHistoryMu sync.Mutex
+	rebootHistory   map[string][]time.Time
+
+	// verifications holds the remediations whose outcome is not known yet.
+	verificationsMu sync.Mutex
+	verifications   map[string]*verification
+
+	// nodeStatuses holds where each node is in its remediation lifecycle.
+	nodeStatusesMu sync.Mutex
+	nodeStatuses   map[string]*nodeStatus
+
+	// lastReconcileAt is when the last reconcile finished, or when Run started before the first one did.
+	lastReconcileMu sync.Mutex
+	lastReconcileAt time.Time
+
 	nodeSelector *metav1.LabelSelector
 }
 
 func NewWatcher(ctx context.Context, apiURL, apiKey, region, clusterID, nodePoolID string, opts ...Option) (Watcher, error) {
 	w := &watcher{
-		clusterID: clusterID,
-		apiKey:    apiKey,
-		apiURL:    apiURL,
-		region:    region,
+		clusterID:  clusterID,
+		nodePoolID: nodePoolID,
+		apiKey:     apiKey,
+		apiURL:     apiURL,
+		region:     region,
+
+		rebootHistory: make(map[string][]time.Time),
+		verifications: make(map[string]*verification),
+		nodeStatuses:  make(map[string]*nodeStatus),
 	}
 	for _, opt := range append(defaultOptions, opts...) {
 		opt(w)
@@ -67,6 +198,31 @@ func NewWatcher(ctx context.Context, apiURL, apiKey, region, clusterID, nodePool
 		return nil, fmt.Errorf("CIVO_API_KEY not set")
 	}
 
+	w.healthChecks = append([]HealthCheck{
+		NewGPUCountCheck(w.nodeDesiredGPUCount),
+		NewReadyCheck(),
+	}, w.healthChecks...)
+
+	ruleChecks, err := parseUnhealthyRules(w.unhealthyRules)
+	if err != nil {
+		return nil, err
+	}
+	w.healthChecks = append(w.healthChecks, ruleChecks...)
+
+	maintenance, err := newMaintenancePolicy(w.rebootWindows, w.rebootBlackouts, w.rebootWindowTimezone)
+	if err != nil {
+		return nil, err
+	}
+	w.maintenance = maintenance
+
+	if w.stuckPodCleanup {
+		policy, err := newStuckPodPolicy(w.stuckPodNamespaces, w.stuckPodSelector)
+		if err != nil {
+			return nil, err
+		}
+		w.stuckPodPolicy = policy
+	}
+
 	w.nodeSelector = &metav1.LabelSelector{
 		MatchLabels: map[string]string{
 			nodePoolLabelKey: nodePoolID,
@@ -79,6 +235,19 @@ func NewWatcher(ctx context.Context, apiURL, apiKey, region, clusterID, nodePool
 	if err := w.setupCivoClient(); err != nil {
 		return nil, err
 	}
+	if err := w.setupRemediator(); err != nil {
+		return nil, err
+	}
+	if err := w.setupDevicePlugin(); err != nil {
+		return nil, err
+	}
+	if err := w.setupNotifier(); err != nil {
+		return nil, err
+	}
+	if w.approver == nil && w.approvalURL != "" {
+		w.approver = NewWebhookApprover(w.approvalURL, w.approvalTimeout)
+	}
+	w.setupEventRecorder()
 	return w, nil
 }
 
@@ -132,23 +301,129 @@ func (w *watcher) setupCivoClient() error {
 	return nil
 }
 
+// setupRemediator creates the built-in Remediator selected by name, unless one has been set with an Option.
+func (w *watcher) setupRemediator() error {
+	if w.remediator != nil {
+		return nil
+	}
+
+	remediator, err := newRemediator(w.remediationName, w.client, w.civoClient, w.clusterID)
+	if err != nil {
+		return err
+	}
+	w.remediator = remediator
+	return nil
+}
+
+// setupDevicePlugin adds the device plugin check, and restarts crash looping device plugin pods
+// before falling back to the remediator, when the device plugin namespace is set.
+func (w *watcher) setupDevicePlugin() error {
+	if w.devicePluginNamespace == "" {
+		return nil
+	}
+
+	selector, err := labels.Parse(w.devicePluginSelector)
+	if err != nil {
+		return fmt.Errorf("invalid device plugin selector %q: %w", w.devicePluginSelector, err)
+	}
+	w.healthChecks = append(w.healthChecks, NewDevicePluginCheck(w.client, w.devicePluginNamespace, selector))
+	w.remediator = NewDevicePluginRemediator(w.client, w.devicePluginNamespace, selector, w.devicePluginRestartPeriod, w.remediator)
+	return nil
+}
+
+// setupNotifier creates the webhook notifier when the webhook URL is set, unless a notifier has been set with an Option.
+func (w *watcher) setupNotifier() error {
+	if w.notifier != nil || w.webhookURL == "" {
+		return nil
+	}
+
+	notifier, err := NewWebhookNotifier(w.webhookURL, w.webhookTemplate, parseNotificationEvents(w.webhookEvents), w.webhookRetries)
+	if err != nil {
+		return err
+	}
+	w.notifier = notifier
+	return nil
+}
+
+// setupEventRecorder creates the recorder of the events node-agent records on nodes,
+// unless one has been set with an Option.
+func (w *watcher) setupEventRecorder() {
+	if w.eventRecorder != nil {
+		return
+	}
+
+	w.eventBroadcaster = record.NewBroadcaster()
+	sink := &eventSink{
+		sink: &typedcorev1.EventSinkImpl{
+			Interface: w.client.CoreV1().Events(""),
+		},
+		correlator: record.NewEventCorrelatorWithOptions(record.CorrelatorOptions{}),
+		pending:    &w.pendingEvents,
+	}
+	w.eventBroadcaster.StartEventWatcher(sink.record)
+	w.eventRecorder = &pendingEventRecorder{
+		EventRecorder: w.eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{
+			Component: "node-agent",
+		}),
+		pending: &w.pendingEvents,
+	}
+}
+
 func (w *watcher) Run(ctx context.Context) error {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+	defer w.stopRecordingEvents()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer w.notifications.Wait()
+
+	serveErrCh := make(chan error, 1)
+	if w.httpAddress != "" {
+		go func() {
+			serveErrCh <- w.serve(ctx)
+		}()
+	}
+
+	w.markReconciled(time.Now())
+	// The first reconcile starts right away instead of after the first interval.
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
-			slog.Info("Started the watcher process...")
-			if err := w.run(ctx); err != nil {
-				slog.Error("An error occurred while running the watcher process", "error", err)
-			}
+		case <-timer.C:
+			w.reconcile(ctx)
+			timer.Reset(w.nextReconcileDelay())
+		case err := <-serveErrCh:
+			return err
 		case <-ctx.Done():
 			return nil
 		}
 	}
 }
 
+// reconcile runs a single reconcile, which is cancelled when it takes longer than the reconcile timeout.
+func (w *watcher) reconcile(ctx context.Context) {
+	if w.reconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.reconcileTimeout)
+		defer cancel()
+	}
+
+	slog.Info("Started the watcher process...")
+	if err := w.run(ctx); err != nil {
+		slog.Error("An error occurred while running the watcher process", "error", err)
+	}
+	w.markReconciled(time.Now())
+}
+
+// nextReconcileDelay returns how long to wait before the next reconcile. A random jitter is added to the
+// reconcile interval, so that node-agents of many clusters do not call the Civo API at the same time.
+func (w *watcher) nextReconcileDelay() time.Duration {
+	if w.reconcileJitter <= 0 {
+		return w.reconcileInterval
+	}
+	return w.reconcileInterval + rand.N(w.reconcileJitter)
+}
+
 func (w *watcher) run(ctx context.Context) error {
 	nodes, err := w.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{
 		LabelSelector: metav1.FormatLabelSelector(w.nodeSelector),
@@ -157,36 +432,201 @@ func (w *watcher) run(ctx context.Context) error {
 		return err
 	}
 
-	thresholdTime := time.Now().Add(-w.rebootTimeWindowMinutes * time.Minute)
+	pauseReason, err := w.clusterPauseReason(ctx)
+	if err != nil {
+		return err
+	}
+	if pauseReason != "" {
+		slog.Info("Remediation is paused because the cluster is not in a steady state", "reason", pauseReason)
+		remediationPaused.WithLabelValues(w.clusterID, w.nodePoolID).Set(1)
+	} else {
+		remediationPaused.WithLabelValues(w.clusterID, w.nodePoolID).Set(0)
+	}
+
+	ctx = w.prefetchChecks(ctx)
+	now := time.Now()
+	w.verifyRemediations(ctx, nodes.Items, now)
+
+	existing := make(map[string]bool, len(nodes.Items))
 
 	for _, node := range nodes.Items {
-		if !isNodeDesiredGPU(&node, w.nodeDesiredGPUCount) || !isNodeReady(&node) {
-
-			// LTT:  LastTransitionTime of node.
-			// LRCT: LastRebootCmdTimes
-			// 60:   Threshold time (example)
-			// - LTT > 60 , LRCT < 60 dont reboot
-			// - LTT < 60 , LRCT < 60 dont reboot
-			// - LTT < 60 , LRCT > 60 dont reboot
-			// - LTT > 60, LRCT >. 60 reboot
-			slog.Info("Node is not ready, attempting to reboot", "node", node.GetName())
-			if isReadyOrNotReadyStatusChangedAfter(&node, thresholdTime) {
-				slog.Info("Skipping reboot because Ready/NotReady status was updated recently", "node", node.GetName())
-				continue
+		existing[node.GetName()] = true
+		switch state, seen := w.nodeStateOf(node.GetName()); {
+		case !seen && isNodeQuarantined(&node):
+			// The node was quarantined before node-agent restarted.
+			w.transition(node.GetName(), nodeEventQuarantined, "quarantine taint was found", now)
+		case state == nodeStateQuarantined && !isNodeQuarantined(&node):
+			w.transition(node.GetName(), nodeEventReleased, "quarantine taint was removed", now)
+		}
+
+		w.restoreLastRemediation(&node)
+		eval := withRebootRequest(&node, w.evaluateNode(ctx, &node))
+		if !eval.needsRemediation() {
+			w.unhealthySince.Delete(node.GetName())
+			w.transition(node.GetName(), nodeEventObservedHealthy, eval.reason(), now)
+		} else {
+			if _, loaded := w.unhealthySince.LoadOrStore(node.GetName(), now); !loaded {
+				w.notify(ctx, Notification{
+					Event:      NotificationUnhealthy,
+					Node:       node.GetName(),
+					InstanceID: instanceIDFromProviderID(node.Spec.ProviderID),
+					Reason:     eval.reason(),
+					Attempt:    len(w.rebootHistoryOf(node.GetName())),
+				})
 			}
-			if w.isLastRebootCommandTimeAfter(node.GetName(), thresholdTime) {
-				slog.Info("Skipping reboot because Reboot command was executed recently", "node", node.GetName())
-				continue
+			w.transition(node.GetName(), nodeEventObservedUnhealthy, eval.reason(), now)
+			slog.Info("Node is unhealthy, attempting to reboot", "node", node.GetName(), "reason", eval.reason())
+		}
+
+		d := w.decide(&node, eval, now, pauseReason)
+		changed := w.recordDecision(&node, eval, d, now)
+		switch d.action {
+		case decisionSkip, decisionQueue:
+			slog.Info(d.message, "node", node.GetName(), "rule", d.rule, "reason", d.reason)
+			if changed && d.notifiesSkipped() {
+				w.notify(ctx, Notification{
+					Event:      NotificationSkipped,
+					Node:       node.GetName(),
+					InstanceID: instanceIDFromProviderID(node.Spec.ProviderID),
+					Reason:     d.reason,
+					Attempt:    len(w.rebootHistoryOf(node.GetName())),
+				})
 			}
-			if err := w.rebootNode(node.GetName()); err != nil {
+		case decisionQuarantine:
+			if err := w.quarantineNode(ctx, node.GetName(), d.reason); err != nil {
+				slog.Error("Failed to quarantine Node", "node", node.GetName(), "error", err)
+				return err
+			}
+		case decisionRemediate:
+			if d.rule != ruleUnhealthy {
+				slog.Info(d.message, "node", node.GetName(), "rule", d.rule, "reason", d.reason)
+			}
+			result, err := w.remediateNode(ctx, &node, eval.reason())
+			if err != nil {
 				slog.Error("Failed to reboot Node", "node", node.GetName(), "error", err)
 				return fmt.Errorf("failed to reboot node: %w", err)
 			}
+			if requested, ok := pendingRebootRequest(&node); ok && !result.Skipped {
+				if err := w.acknowledgeRebootRequest(ctx, node.GetName(), requested); err != nil {
+					slog.Error("Failed to acknowledge reboot request", "node", node.GetName(), "error", err)
+					return err
+				}
+			}
 		}
 	}
+	w.forgetNodes(existing)
 	return nil
 }
 
+// clusterPauseReason checks the Kubernetes cluster and the node pool in the Civo API and
+// returns why remediation has to be paused. While the cluster is being upgraded, or the pool is
+// being scaled or rebuilt, nodes legitimately go NotReady, so rebooting them would only get in the way.
+// An empty reason means the cluster is in a steady state.
+func (w *watcher) clusterPauseReason(ctx context.Context) (string, error) {
+	cluster, err := callCivo(ctx, func() (*civogo.KubernetesCluster, error) {
+		return w.civoClient.GetKubernetesCluster(w.clusterID)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster, clusterID: %s: %w", w.clusterID, err)
+	}
+
+	if !strings.EqualFold(cluster.Status, clusterStatusActive) {
+		return fmt.Sprintf("cluster status is %s", cluster.Status), nil
+	}
+	if !cluster.Ready {
+		return "cluster is not ready", nil
+	}
+
+	for _, pool := range cluster.Pools {
+		if pool.ID != w.nodePoolID {
+			continue
+		}
+		if pool.Count != len(pool.Instances) {
+			return fmt.Sprintf("node pool is scaling from %d to %d instances", len(pool.Instances), pool.Count), nil
+		}
+		for _, instance := range pool.Instances {
+			switch strings.ToUpper(instance.Status) {
+			case instanceStatusBuildPending, instanceStatusBuilding:
+				return fmt.Sprintf("instance %s in node pool is being built", instance.Hostname), nil
+			case instanceStatusDeleting, instanceStatusDeleted:
+				return fmt.Sprintf("instance %s in node pool is being deleted", instance.Hostname), nil
+			}
+		}
+		return "", nil
+	}
+	return "node pool not found in cluster", nil
+}
+
+// unhealthyDuration returns how long the node has been unhealthy. It is the time since node-agent first saw
+// the node unhealthy, or since the node became NotReady if that is earlier, e.g. because node-agent was restarted.
+func (w *watcher) unhealthyDuration(node *corev1.Node, now time.Time) time.Duration {
+	since := now
+	if v, ok := w.unhealthySince.Load(node.GetName()); ok {
+		if t, ok := v.(time.Time); ok {
+			since = t
+		}
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue &&
+			!cond.LastTransitionTime.IsZero() && cond.LastTransitionTime.Time.Before(since) {
+			since = cond.LastTransitionTime.Time
+		}
+	}
+	return now.Sub(since)
+}
+
+// isNodeManaged checks if node-agent is allowed to remediate the node, and returns the reason when it is not.
+// A node is excluded when it has the disabled label or annotation set to true. In opt-in mode, only nodes
+// that have the enabled label or annotation set to true are remediated.
+func isNodeManaged(node *corev1.Node, optIn bool) (bool, string) {
+	if hasTrueLabelOrAnnotation(node, disabledKey) {
+		return false, fmt.Sprintf("%s is set to true", disabledKey)
+	}
+	if optIn && !hasTrueLabelOrAnnotation(node, enabledKey) {
+		return false, fmt.Sprintf("opt-in mode is enabled and %s is not set to true", enabledKey)
+	}
+	return true, ""
+}
+
+// hasTrueLabelOrAnnotation checks if either the label or the annotation with the given key is set to true on the node.
+func hasTrueLabelOrAnnotation(node *corev1.Node, key string) bool {
+	for _, m := range []map[string]string{node.GetLabels(), node.GetAnnotations()} {
+		v, ok := m[key]
+		if !ok {
+			continue
+		}
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil && b {
+			return true
+		}
+	}
+	return false
+}
+
+// isNodeUnderMaintenance checks if an operator is working on the node, and returns the reason when they are.
+// A node is considered under maintenance when it has been cordoned by someone other than node-agent
+// (if skipCordoned is true), or when it has a taint with one of the given keys.
+func isNodeUnderMaintenance(node *corev1.Node, skipCordoned bool, taintKeys []string) (bool, string) {
+	if skipCordoned && node.Spec.Unschedulable && !hasTrueLabelOrAnnotation(node, cordonedByAgentKey) {
+		return true, "node is cordoned"
+	}
+	for _, taint := range node.Spec.Taints {
+		if slices.Contains(taintKeys, taint.Key) {
+			return true, fmt.Sprintf("node has maintenance taint %s", taint.ToString())
+		}
+	}
+	return false, ""
+}
+
+// isNodeInStartupGracePeriod checks if the node was created less than gracePeriod ago.
+// Fresh GPU nodes can take many minutes to install drivers and advertise their GPUs,
+// so they must not be remediated before the grace period has elapsed.
+func isNodeInStartupGracePeriod(node *corev1.Node, now time.Time, gracePeriod time.Duration) bool {
+	if gracePeriod <= 0 || node.CreationTimestamp.IsZero() {
+		return false
+	}
+	return now.Sub(node.CreationTimestamp.Time) < gracePeriod
+}
+
 func isReadyOrNotReadyStatusChangedAfter(node *corev1.Node, thresholdTime time.Time) bool {
 	var lastChangedTime time.Time
 	for _, cond := range node.Status.Conditions {
@@ -272,17 +712,72 @@ func isNodeDesiredGPU(node *corev1.Node, desired int) bool {
 	return gpuCount == int64(desired)
 }
 
-func (w *watcher) rebootNode(name string) error {
-	instance, err := w.civoClient.FindKubernetesClusterInstance(w.clusterID, name)
+// remediateNode remediates the unhealthy node with the configured Remediator,
+// and keeps track of the remediation so that its outcome can be verified.
+// It returns the result of the remediation, which is skipped when the remediation was denied or would be skipped.
+// The node only moves to Remediating once the remediation is taken, so that skipped remediations leave its state as is.
+func (w *watcher) remediateNode(ctx context.Context, node *corev1.Node, reason string) (result RemediationResult, err error) {
+	name := node.GetName()
+	defer func() {
+		if err != nil {
+			w.transition(name, nodeEventRemediationErrored, err.Error(), time.Now())
+			w.notify(ctx, Notification{
+				Event:      NotificationFailed,
+				Node:       name,
+				InstanceID: instanceIDFromProviderID(node.Spec.ProviderID),
+				Action:     w.remediator.Name(),
+				Reason:     err.Error(),
+				Attempt:    len(w.rebootHistoryOf(name)) + 1,
+			})
+		}
+	}()
+
+	// Remediations that would be skipped are neither proposed to the approver nor started.
+	skipReason, err := remediationSkipReason(ctx, w.remediator, node)
 	if err != nil {
-		return fmt.Errorf("failed to find instance, clusterID: %s, nodeName: %s: %w", w.clusterID, name, err)
+		return RemediationResult{}, err
+	}
+	if skipReason != "" {
+		slog.Info("Skipping remediation", "node", name, "reason", skipReason)
+		return RemediationResult{Skipped: true, Reason: skipReason}, nil
+	}
+	if approval := w.approve(ctx, node, reason); !approval.Approved {
+		return RemediationResult{Skipped: true, Reason: "remediation was denied: " + approval.Reason}, nil
 	}
 
-	_, err = w.civoClient.HardRebootInstance(instance.ID)
+	w.transition(name, nodeEventRemediationStarted, reason, time.Now())
+	result, err = w.remediator.Remediate(ctx, node)
 	if err != nil {
-		return fmt.Errorf("failed to reboot instance, clusterID: %s, instanceID: %s: %w", w.clusterID, instance.ID, err)
+		return RemediationResult{}, err
 	}
-	slog.Info("Instance is rebooting", "instanceID", instance.ID, "node", name)
-	w.lastRebootCmdTimes.Store(name, time.Now())
-	return nil
+	if result.Skipped {
+		w.transition(name, nodeEventRemediationSkipped, result.Reason, time.Now())
+		return result, nil
+	}
+
+	w.eventRecorder.Eventf(nodeReference(name), corev1.EventTypeNormal, eventReasonRemediating,
+		"Remediating unhealthy node with %s (%s)", result.Action, result.Reason)
+
+	now := time.Now()
+	w.recordInstanceID(name, result.InstanceID)
+	w.lastRebootCmdTimes.Store(name, now)
+	if err := w.annotateLastRemediation(ctx, name, now); err != nil && !apierrors.IsNotFound(err) {
+		slog.Warn("Failed to annotate the last remediation time", "node", name, "error", err)
+	}
+	if !result.Lightweight {
+		w.recordReboot(name, now)
+	}
+	w.transition(name, nodeEventRemediationIssued, result.Reason, now)
+	if !result.Unverifiable {
+		w.startVerification(name, result, now)
+	}
+	w.notify(ctx, Notification{
+		Event:      NotificationRemediated,
+		Node:       name,
+		InstanceID: cmp.Or(result.InstanceID, instanceIDFromProviderID(node.Spec.ProviderID)),
+		Action:     result.Action,
+		Reason:     reason,
+		Attempt:    len(w.rebootHistoryOf(name)),
+	})
+	return result, nil
 }
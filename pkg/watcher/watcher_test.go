@@ -1,8 +1,10 @@
 package watcher
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
@@ -26,6 +28,36 @@ var (
 	testRebootTimeWindowMinutes = time.Duration(40)
 )
 
+// newFakeClient returns a FakeClient whose cluster and node pool are in a steady state.
+func newFakeClient() *FakeClient {
+	return &FakeClient{
+		GetKubernetesClusterFunc: func(id string) (*civogo.KubernetesCluster, error) {
+			return newSteadyCluster(id), nil
+		},
+	}
+}
+
+func newSteadyCluster(id string) *civogo.KubernetesCluster {
+	return &civogo.KubernetesCluster{
+		ID:     id,
+		Status: "ACTIVE",
+		Ready:  true,
+		Pools: []civogo.KubernetesPool{
+			{
+				ID:    testNodePoolID,
+				Count: 1,
+				Instances: []civogo.KubernetesInstance{
+					{
+						ID:       "instance-01",
+						Hostname: "node-01",
+						Status:   "ACTIVE",
+					},
+				},
+			},
+		},
+	}
+}
+
 func TestNew(t *testing.T) {
 	type args struct {
 		clusterID  string
@@ -141,6 +173,22 @@ func TestNew(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "Returns an error when reboot window is invalid",
+			args: args{
+				clusterID:  testClusterID,
+				region:     testRegion,
+				apiKey:     testApiKey,
+				apiURL:     testApiURL,
+				nodePoolID: testNodePoolID,
+				opts: []Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(&FakeClient{}),
+					WithRebootWindows("invalid window"),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Returns an error when clusterID is missing",
 			args: args{
@@ -204,7 +252,7 @@ func TestRun(t *testing.T) {
 			args: args{
 				opts: []Option{
 					WithKubernetesClient(fake.NewSimpleClientset()),
-					WithCivoClient(&FakeClient{}),
+					WithCivoClient(newFakeClient()),
 					WithDesiredGPUCount(testNodeDesiredGPUCount),
 				},
 				nodePoolID: testNodePoolID,
@@ -250,7 +298,7 @@ func TestRun(t *testing.T) {
 			args: args{
 				opts: []Option{
 					WithKubernetesClient(fake.NewSimpleClientset()),
-					WithCivoClient(&FakeClient{}),
+					WithCivoClient(newFakeClient()),
 					WithDesiredGPUCount(testNodeDesiredGPUCount),
 				},
 				nodePoolID: testNodePoolID,
@@ -307,7 +355,7 @@ func TestRun(t *testing.T) {
 			args: args{
 				opts: []Option{
 					WithKubernetesClient(fake.NewSimpleClientset()),
-					WithCivoClient(&FakeClient{}),
+					WithCivoClient(newFakeClient()),
 					WithDesiredGPUCount(testNodeDesiredGPUCount),
 				},
 				nodePoolID: testNodePoolID,
@@ -360,7 +408,7 @@ func TestRun(t *testing.T) {
 			args: args{
 				opts: []Option{
 					WithKubernetesClient(fake.NewSimpleClientset()),
-					WithCivoClient(&FakeClient{}),
+					WithCivoClient(newFakeClient()),
 					WithDesiredGPUCount(testNodeDesiredGPUCount),
 				},
 				nodePoolID: testNodePoolID,
@@ -404,7 +452,7 @@ func TestRun(t *testing.T) {
 			args: args{
 				opts: []Option{
 					WithKubernetesClient(fake.NewSimpleClientset()),
-					WithCivoClient(&FakeClient{}),
+					WithCivoClient(newFakeClient()),
 					WithDesiredGPUCount(testNodeDesiredGPUCount),
 				},
 				nodePoolID: testNodePoolID,
@@ -447,7 +495,7 @@ func TestRun(t *testing.T) {
 			args: args{
 				opts: []Option{
 					WithKubernetesClient(fake.NewSimpleClientset()),
-					WithCivoClient(&FakeClient{}),
+					WithCivoClient(newFakeClient()),
 					WithDesiredGPUCount(testNodeDesiredGPUCount),
 				},
 				nodePoolID: testNodePoolID,
@@ -468,7 +516,55 @@ func TestRun(t *testing.T) {
 			args: args{
 				opts: []Option{
 					WithKubernetesClient(fake.NewSimpleClientset()),
-					WithCivoClient(&FakeClient{}),
+					WithCivoClient(newFakeClient()),
+					WithDesiredGPUCount(testNodeDesiredGPUCount),
+				},
+				nodePoolID: testNodePoolID,
+			},
+			beforeFunc: func(w *watcher) {
+				t.Helper()
+				client := w.client.(*fake.Clientset)
+
+				nodes := &corev1.NodeList{
+					Items: []corev1.Node{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "node-01",
+								Labels: map[string]string{
+									nodePoolLabelKey: testNodePoolID,
+								},
+							},
+							Status: corev1.NodeStatus{
+								Conditions: []corev1.NodeCondition{
+									{
+										Type:   corev1.NodeReady,
+										Status: corev1.ConditionFalse,
+									},
+								},
+								Allocatable: corev1.ResourceList{
+									gpuResourceName: resource.MustParse("8"),
+								},
+							},
+						},
+					},
+				}
+				client.Fake.PrependReactor("list", "nodes", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nodes, nil
+				})
+
+				civoClient := w.civoClient.(*FakeClient)
+				civoClient.FindKubernetesClusterInstanceFunc = func(clusterID, search string) (*civogo.Instance, error) {
+					return nil, errors.New("invalid error")
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns nil and skips reboot when node is not ready but the cluster is being upgraded",
+			args: args{
+				opts: []Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(newFakeClient()),
 					WithDesiredGPUCount(testNodeDesiredGPUCount),
 				},
 				nodePoolID: testNodePoolID,
@@ -505,7 +601,29 @@ func TestRun(t *testing.T) {
 				})
 
 				civoClient := w.civoClient.(*FakeClient)
+				civoClient.GetKubernetesClusterFunc = func(id string) (*civogo.KubernetesCluster, error) {
+					return &civogo.KubernetesCluster{ID: id, Status: "UPGRADING"}, nil
+				}
 				civoClient.FindKubernetesClusterInstanceFunc = func(clusterID, search string) (*civogo.Instance, error) {
+					t.Errorf("FindKubernetesClusterInstance must not be called while remediation is paused")
+					return nil, errors.New("invalid error")
+				}
+			},
+		},
+		{
+			name: "Returns an error when unable to get the cluster",
+			args: args{
+				opts: []Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(newFakeClient()),
+					WithDesiredGPUCount(testNodeDesiredGPUCount),
+				},
+				nodePoolID: testNodePoolID,
+			},
+			beforeFunc: func(w *watcher) {
+				t.Helper()
+				civoClient := w.civoClient.(*FakeClient)
+				civoClient.GetKubernetesClusterFunc = func(id string) (*civogo.KubernetesCluster, error) {
 					return nil, errors.New("invalid error")
 				}
 			},
@@ -873,7 +991,7 @@ func TestIsNodeDesiredGPU(t *testing.T) {
 	}
 }
 
-func TestRebootNode(t *testing.T) {
+func TestRemediateNode(t *testing.T) {
 	type args struct {
 		nodeName string
 		opts     []Option
@@ -965,6 +1083,91 @@ func TestRebootNode(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns nil and skips reboot when instance is already rebooting",
+			args: args{
+				nodeName: "node-01",
+				opts: []Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(&FakeClient{}),
+					WithDesiredGPUCount(testNodeDesiredGPUCount),
+				},
+			},
+			beforeFunc: func(t *testing.T, w *watcher) {
+				t.Helper()
+				client := w.civoClient.(*FakeClient)
+
+				instance := &civogo.Instance{
+					ID:     "instance-01",
+					Status: "REBOOTING",
+				}
+
+				client.FindKubernetesClusterInstanceFunc = func(clusterID, search string) (*civogo.Instance, error) {
+					return instance, nil
+				}
+				client.HardRebootInstanceFunc = func(id string) (*civogo.SimpleResponse, error) {
+					t.Errorf("HardRebootInstance must not be called for instance with status %s", instance.Status)
+					return new(civogo.SimpleResponse), nil
+				}
+			},
+		},
+		{
+			name: "Returns nil and starts instance instead of rebooting when instance is stopped",
+			args: args{
+				nodeName: "node-01",
+				opts: []Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(&FakeClient{}),
+					WithDesiredGPUCount(testNodeDesiredGPUCount),
+				},
+			},
+			beforeFunc: func(t *testing.T, w *watcher) {
+				t.Helper()
+				client := w.civoClient.(*FakeClient)
+
+				instance := &civogo.Instance{
+					ID:     "instance-01",
+					Status: "SHUTOFF",
+				}
+
+				client.FindKubernetesClusterInstanceFunc = func(clusterID, search string) (*civogo.Instance, error) {
+					return instance, nil
+				}
+				client.HardRebootInstanceFunc = func(id string) (*civogo.SimpleResponse, error) {
+					t.Errorf("HardRebootInstance must not be called for instance with status %s", instance.Status)
+					return new(civogo.SimpleResponse), nil
+				}
+				client.StartInstanceFunc = func(id string) (*civogo.SimpleResponse, error) {
+					if instance.ID != id {
+						t.Errorf("instanceId dose not match. want: %s, but got: %s", instance.ID, id)
+					}
+					return new(civogo.SimpleResponse), nil
+				}
+			},
+		},
+		{
+			name: "Returns an error when starting a stopped instance fails",
+			args: args{
+				nodeName: "node-01",
+				opts: []Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(&FakeClient{}),
+					WithDesiredGPUCount(testNodeDesiredGPUCount),
+				},
+			},
+			beforeFunc: func(t *testing.T, w *watcher) {
+				t.Helper()
+				client := w.civoClient.(*FakeClient)
+
+				client.FindKubernetesClusterInstanceFunc = func(clusterID, search string) (*civogo.Instance, error) {
+					return &civogo.Instance{ID: "instance-01", Status: "STOPPED"}, nil
+				}
+				client.StartInstanceFunc = func(id string) (*civogo.SimpleResponse, error) {
+					return nil, errors.New("invalid error")
+				}
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -980,10 +1183,704 @@ func TestRebootNode(t *testing.T) {
 				test.beforeFunc(t, obj)
 			}
 
-			err = obj.rebootNode(test.args.nodeName)
+			_, err = obj.remediateNode(t.Context(), &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: test.args.nodeName,
+				},
+			}, "node is not ready")
 			if (err != nil) != test.wantErr {
 				t.Errorf("error = %v, wantErr %v", err, test.wantErr)
 			}
 		})
 	}
 }
+
+func TestInstanceActionFor(t *testing.T) {
+	type test struct {
+		name   string
+		status string
+		want   instanceAction
+	}
+
+	tests := []test{
+		{
+			name:   "Returns reboot when instance is active",
+			status: "ACTIVE",
+			want:   instanceActionReboot,
+		},
+		{
+			name:   "Returns reboot when instance status is unknown",
+			status: "",
+			want:   instanceActionReboot,
+		},
+		{
+			name:   "Returns skip when instance is already rebooting",
+			status: "REBOOTING",
+			want:   instanceActionSkip,
+		},
+		{
+			name:   "Returns skip when instance is being built",
+			status: "BUILDING",
+			want:   instanceActionSkip,
+		},
+		{
+			name:   "Returns skip when instance is being deleted",
+			status: "DELETING",
+			want:   instanceActionSkip,
+		},
+		{
+			name:   "Returns start when instance is stopped",
+			status: "SHUTOFF",
+			want:   instanceActionStart,
+		},
+		{
+			name:   "Returns start when instance status is lower case",
+			status: "stopped",
+			want:   instanceActionStart,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, reason := instanceActionFor(test.status)
+			if got != test.want {
+				t.Errorf("got = %v, want %v", got, test.want)
+			}
+			if reason == "" {
+				t.Errorf("reason is empty")
+			}
+		})
+	}
+}
+
+func TestClusterPauseReason(t *testing.T) {
+	type test struct {
+		name       string
+		cluster    func() *civogo.KubernetesCluster
+		wantPaused bool
+	}
+
+	tests := []test{
+		{
+			name: "Returns empty reason when cluster and node pool are in a steady state",
+			cluster: func() *civogo.KubernetesCluster {
+				return newSteadyCluster(testClusterID)
+			},
+		},
+		{
+			name: "Returns reason when cluster is being upgraded",
+			cluster: func() *civogo.KubernetesCluster {
+				c := newSteadyCluster(testClusterID)
+				c.Status = "UPGRADING"
+				return c
+			},
+			wantPaused: true,
+		},
+		{
+			name: "Returns reason when cluster is not ready",
+			cluster: func() *civogo.KubernetesCluster {
+				c := newSteadyCluster(testClusterID)
+				c.Ready = false
+				return c
+			},
+			wantPaused: true,
+		},
+		{
+			name: "Returns reason when node pool is scaling",
+			cluster: func() *civogo.KubernetesCluster {
+				c := newSteadyCluster(testClusterID)
+				c.Pools[0].Count = 2
+				return c
+			},
+			wantPaused: true,
+		},
+		{
+			name: "Returns reason when an instance in the node pool is being built",
+			cluster: func() *civogo.KubernetesCluster {
+				c := newSteadyCluster(testClusterID)
+				c.Pools[0].Instances[0].Status = "BUILDING"
+				return c
+			},
+			wantPaused: true,
+		},
+		{
+			name: "Returns empty reason when an instance in the node pool is stopped",
+			cluster: func() *civogo.KubernetesCluster {
+				c := newSteadyCluster(testClusterID)
+				c.Pools[0].Instances[0].Status = "SHUTOFF"
+				return c
+			},
+		},
+		{
+			name: "Returns reason when node pool is not found",
+			cluster: func() *civogo.KubernetesCluster {
+				c := newSteadyCluster(testClusterID)
+				c.Pools = nil
+				return c
+			},
+			wantPaused: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			civoClient := &FakeClient{
+				GetKubernetesClusterFunc: func(id string) (*civogo.KubernetesCluster, error) {
+					return test.cluster(), nil
+				},
+			}
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset()),
+				WithCivoClient(civoClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reason, err := w.(*watcher).clusterPauseReason(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if paused := reason != ""; paused != test.wantPaused {
+				t.Errorf("paused = %v (reason: %q), want %v", paused, reason, test.wantPaused)
+			}
+		})
+	}
+}
+
+func TestIsNodeInStartupGracePeriod(t *testing.T) {
+	now := time.Now()
+
+	type test struct {
+		name        string
+		node        *corev1.Node
+		gracePeriod time.Duration
+		want        bool
+	}
+
+	tests := []test{
+		{
+			name: "Returns true when node was created within the grace period",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "node-01",
+					CreationTimestamp: metav1.NewTime(now.Add(-5 * time.Minute)),
+				},
+			},
+			gracePeriod: 15 * time.Minute,
+			want:        true,
+		},
+		{
+			name: "Returns false when node was created before the grace period",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "node-01",
+					CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+				},
+			},
+			gracePeriod: 15 * time.Minute,
+			want:        false,
+		},
+		{
+			name: "Returns false when grace period is disabled",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "node-01",
+					CreationTimestamp: metav1.NewTime(now),
+				},
+			},
+			gracePeriod: 0,
+			want:        false,
+		},
+		{
+			name: "Returns false when creation timestamp is not set",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+			},
+			gracePeriod: 15 * time.Minute,
+			want:        false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := isNodeInStartupGracePeriod(test.node, now, test.gracePeriod)
+			if got != test.want {
+				t.Errorf("got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsNodeManaged(t *testing.T) {
+	type test struct {
+		name  string
+		node  *corev1.Node
+		optIn bool
+		want  bool
+	}
+
+	tests := []test{
+		{
+			name: "Returns true when node has no labels or annotations",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Returns false when node has disabled label",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+					Labels: map[string]string{
+						disabledKey: "true",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Returns false when node has disabled annotation",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+					Annotations: map[string]string{
+						disabledKey: "true",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Returns true when disabled annotation is set to false",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+					Annotations: map[string]string{
+						disabledKey: "false",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Returns false in opt-in mode when node has no enabled label",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+			},
+			optIn: true,
+			want:  false,
+		},
+		{
+			name: "Returns true in opt-in mode when node has enabled label",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+					Labels: map[string]string{
+						enabledKey: "true",
+					},
+				},
+			},
+			optIn: true,
+			want:  true,
+		},
+		{
+			name: "Returns false in opt-in mode when node is both enabled and disabled",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+					Labels: map[string]string{
+						enabledKey: "true",
+					},
+					Annotations: map[string]string{
+						disabledKey: "true",
+					},
+				},
+			},
+			optIn: true,
+			want:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _ := isNodeManaged(test.node, test.optIn)
+			if got != test.want {
+				t.Errorf("got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsNodeUnderMaintenance(t *testing.T) {
+	type test struct {
+		name         string
+		node         *corev1.Node
+		skipCordoned bool
+		taintKeys    []string
+		want         bool
+	}
+
+	tests := []test{
+		{
+			name: "Returns false when node is schedulable and has no taints",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+			},
+			skipCordoned: true,
+			want:         false,
+		},
+		{
+			name: "Returns true when node is cordoned by an operator",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+				Spec: corev1.NodeSpec{
+					Unschedulable: true,
+				},
+			},
+			skipCordoned: true,
+			want:         true,
+		},
+		{
+			name: "Returns false when node is cordoned by node-agent",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+					Annotations: map[string]string{
+						cordonedByAgentKey: "true",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Unschedulable: true,
+				},
+			},
+			skipCordoned: true,
+			want:         false,
+		},
+		{
+			name: "Returns false when node is cordoned but cordoned nodes are not skipped",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+				Spec: corev1.NodeSpec{
+					Unschedulable: true,
+				},
+			},
+			skipCordoned: false,
+			want:         false,
+		},
+		{
+			name: "Returns true when node has a maintenance taint",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{
+							Key:    "example.com/maintenance",
+							Effect: corev1.TaintEffectNoSchedule,
+						},
+					},
+				},
+			},
+			taintKeys: []string{"example.com/maintenance"},
+			want:      true,
+		},
+		{
+			name: "Returns false when node has a taint that is not a maintenance taint",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-01",
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{
+							Key:    "nvidia.com/gpu",
+							Effect: corev1.TaintEffectNoSchedule,
+						},
+					},
+				},
+			},
+			taintKeys: []string{"example.com/maintenance"},
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _ := isNodeUnderMaintenance(test.node, test.skipCordoned, test.taintKeys)
+			if got != test.want {
+				t.Errorf("got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWithMaintenanceTaintKeys(t *testing.T) {
+	type test struct {
+		name string
+		opts []Option
+		want []string
+	}
+
+	tests := []test{
+		{
+			name: "Sets the comma-separated keys",
+			opts: []Option{WithMaintenanceTaintKeys("example.com/maintenance, example.com/drain,")},
+			want: []string{"example.com/maintenance", "example.com/drain"},
+		},
+		{
+			name: "Replaces the keys when the option is applied again",
+			opts: []Option{WithMaintenanceTaintKeys("example.com/maintenance"), WithMaintenanceTaintKeys("example.com/drain")},
+			want: []string{"example.com/drain"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				append([]Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(&FakeClient{}),
+				}, test.opts...)...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := w.(*watcher).maintenanceTaintKeys; !slices.Equal(got, test.want) {
+				t.Errorf("got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNextReconcileDelay(t *testing.T) {
+	type test struct {
+		name    string
+		opts    []Option
+		wantMin time.Duration
+		wantMax time.Duration
+	}
+
+	tests := []test{
+		{
+			name:    "Returns the default interval",
+			wantMin: 10 * time.Second,
+			wantMax: 10 * time.Second,
+		},
+		{
+			name:    "Returns the interval when jitter is disabled",
+			opts:    []Option{WithReconcileIntervalSeconds("30")},
+			wantMin: 30 * time.Second,
+			wantMax: 30 * time.Second,
+		},
+		{
+			name:    "Returns the interval with a jitter",
+			opts:    []Option{WithReconcileIntervalSeconds("30"), WithReconcileJitterSeconds("5")},
+			wantMin: 30 * time.Second,
+			wantMax: 35 * time.Second,
+		},
+		{
+			name:    "Ignores invalid values",
+			opts:    []Option{WithReconcileIntervalSeconds("0"), WithReconcileJitterSeconds("-1")},
+			wantMin: 10 * time.Second,
+			wantMax: 10 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				append([]Option{
+					WithKubernetesClient(fake.NewSimpleClientset()),
+					WithCivoClient(&FakeClient{}),
+				}, test.opts...)...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for range 10 {
+				if got := w.(*watcher).nextReconcileDelay(); got < test.wantMin || got > test.wantMax {
+					t.Errorf("delay = %s, want between %s and %s", got, test.wantMin, test.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestRunReconcilesOnStartup(t *testing.T) {
+	reconciled := make(chan struct{}, 1)
+	civoClient := &FakeClient{
+		GetKubernetesClusterFunc: func(id string) (*civogo.KubernetesCluster, error) {
+			select {
+			case reconciled <- struct{}{}:
+			default:
+			}
+			return newSteadyCluster(id), nil
+		},
+	}
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset()),
+		WithCivoClient(civoClient),
+		WithReconcileIntervalSeconds("3600"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- w.Run(ctx)
+	}()
+
+	select {
+	case <-reconciled:
+	case <-time.After(5 * time.Second):
+		t.Error("first reconcile did not start on startup")
+	}
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// deadlineCheck is a HealthCheck that records the deadline of the context it is evaluated with.
+type deadlineCheck struct {
+	deadline *time.Time
+}
+
+func (deadlineCheck) Name() string {
+	return "Deadline"
+}
+
+func (c deadlineCheck) Check(ctx context.Context, _ *corev1.Node) Verdict {
+	*c.deadline, _ = ctx.Deadline()
+	return Healthy("recorded deadline")
+}
+
+func TestReconcileTimeout(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name         string
+		timeout      string
+		wantDeadline bool
+	}
+
+	tests := []test{
+		{
+			name:         "Cancels reconcile after the timeout",
+			timeout:      "60",
+			wantDeadline: true,
+		},
+		{
+			name:         "Does not cancel reconcile when the timeout is 0",
+			timeout:      "0",
+			wantDeadline: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var deadline time.Time
+			w, err := NewWatcher(t.Context(),
+				testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+				WithKubernetesClient(fake.NewSimpleClientset(node)),
+				WithCivoClient(newFakeClient()),
+				WithReconcileTimeoutSeconds(test.timeout),
+				WithHealthChecks(deadlineCheck{deadline: &deadline}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			w.(*watcher).reconcile(t.Context())
+			if got := !deadline.IsZero(); got != test.wantDeadline {
+				t.Fatalf("deadline = %v, want set %v", deadline, test.wantDeadline)
+			}
+			if test.wantDeadline && (deadline.Before(start.Add(59*time.Second)) || deadline.After(time.Now().Add(60*time.Second))) {
+				t.Errorf("deadline = %v, want about 60s after %v", deadline, start)
+			}
+		})
+	}
+}
+
+func TestReconcileTimeoutStopsWaitingForCivoAPI(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-01",
+			Labels: map[string]string{
+				nodePoolLabelKey: testNodePoolID,
+			},
+		},
+	}
+
+	unblock := make(chan struct{})
+	t.Cleanup(func() { close(unblock) })
+
+	w, err := NewWatcher(t.Context(),
+		testApiURL, testApiKey, testRegion, testClusterID, testNodePoolID,
+		WithKubernetesClient(fake.NewSimpleClientset(node)),
+		WithCivoClient(&FakeClient{
+			GetKubernetesClusterFunc: func(id string) (*civogo.KubernetesCluster, error) {
+				<-unblock
+				return newSteadyCluster(id), nil
+			},
+		}),
+		WithReconcileTimeoutSeconds("1"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := w.(*watcher)
+
+	done := make(chan struct{})
+	go func() {
+		obj.reconcile(t.Context())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("reconcile did not return after the reconcile timeout")
+	}
+	if err := obj.checkLiveness(time.Now()); err != nil {
+		t.Errorf("checkLiveness() = %v, want nil after the reconcile returned", err)
+	}
+}
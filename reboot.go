@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// runReboot remediates a node of the pool on behalf of an operator and returns the exit code.
+func runReboot(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("reboot", flag.ContinueOnError)
+	force := fs.Bool("force", false, "Reboot the node even when a safety check fails")
+	yes := fs.Bool("yes", false, "Do not ask for confirmation")
+	kubeconfigPath := fs.String("kubeconfig", defaultKubeconfigPath(), "Path to the kubeconfig, the in-cluster config is used when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: node-agent reboot [flags] <node>\n\n")
+		fmt.Fprintf(fs.Output(), "Remediates a node of the pool with the configured remediation, after checking that it is in the pool,\n")
+		fmt.Fprintf(fs.Output(), "that it is neither excluded from remediation nor quarantined, that it was not remediated within the\n")
+		fmt.Fprintf(fs.Output(), "reboot time window and that no other node of the pool is not ready.\n\n")
+		fs.PrintDefaults()
+	}
+	nodeName, err := parseNodeArgs(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	w, err := newCLIWatcher(ctx, *kubeconfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create watcher: %v\n", err)
+		return 1
+	}
+	plan, err := w.PlanReboot(ctx, nodeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to check node: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Node:             %s\n", plan.Node)
+	fmt.Printf("Instance:         %s\n", orDash(plan.InstanceID))
+	fmt.Printf("Action:           %s\n", plan.Action)
+	lastRemediation := "-"
+	if plan.LastRemediation != nil {
+		lastRemediation = plan.LastRemediation.Format(time.RFC3339)
+	}
+	fmt.Printf("Last remediation: %s\n", lastRemediation)
+	fmt.Printf("Not ready nodes:  %s\n", orDash(strings.Join(plan.NotReadyNodes, ", ")))
+
+	if len(plan.Problems) > 0 {
+		fmt.Println()
+		for _, problem := range plan.Problems {
+			fmt.Printf("WARNING: %s\n", problem)
+		}
+		if !*force {
+			fmt.Fprintln(os.Stderr, "\nRefusing to reboot the node, use --force to reboot it anyway")
+			return 1
+		}
+	}
+
+	if !*yes && !confirm(os.Stdin, os.Stdout, fmt.Sprintf("\nReboot node %s with %s?", plan.Node, plan.Action)) {
+		fmt.Fprintln(os.Stderr, "Aborted")
+		return 1
+	}
+
+	if err := w.Reboot(ctx, nodeName); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to reboot node: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Node %s is being remediated with %s\n", plan.Node, plan.Action)
+	return 0
+}
+
+// confirm asks the question and checks if it is answered with yes.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}